@@ -1,10 +1,14 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"log"
 	"os"
 
 	"github.com/anthdm/superkit/db"
+	"github.com/anthdm/superkit/event"
+	"github.com/anthdm/superkit/kit/tenant"
 
 	_ "github.com/mattn/go-sqlite3"
 
@@ -16,11 +20,45 @@ import (
 // Change this type based on the database package of your likings.
 var dbInstance *gorm.DB
 
+// sqlInstance is the *sql.DB the Gorm DB instance is built on.
+var sqlInstance *sql.DB
+
 // Get returns the instantiated DB instance.
 func Get() *gorm.DB {
 	return dbInstance
 }
 
+// SQL returns the *sql.DB underneath the DB instance, for packages that
+// work with database/sql, like the Unique rule of the validate package.
+func SQL() *sql.DB {
+	return sqlInstance
+}
+
+// Outbox stores events in the event_outbox table within the transaction
+// of the change they belong to. The relay is started in main.go.
+//
+//	db.Get().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+//		...
+//		return auth.UserSignupEvent.EmitOutbox(ctx, db.Outbox, tx.Statement.ConnPool, user)
+//	})
+var Outbox = &event.Outbox{}
+
+// TenantScope scopes queries to the tenant of the given context when
+// using the shared schema (tenant_id column) mode of the kit/tenant package.
+// Queries fail with tenant.ErrNoTenant when the context has no tenant.
+//
+//	db.Get().WithContext(ctx).Scopes(db.TenantScope(ctx)).Find(&projects)
+func TenantScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		cond, id, err := tenant.Where(ctx)
+		if err != nil {
+			tx.AddError(err)
+			return tx
+		}
+		return tx.Where(cond, id)
+	}
+}
+
 func init() {
 	// Create a default *sql.DB exposed by the superkit/db package
 	// based on the given configuration.
@@ -30,11 +68,14 @@ func init() {
 		Password: os.Getenv("DB_PASSWORD"),
 		User:     os.Getenv("DB_USER"),
 		Host:     os.Getenv("DB_HOST"),
+		Trace:    len(os.Getenv("SUPERKIT_TRACE_EXPORTER")) > 0,
 	}
 	dbinst, err := db.NewSQL(config)
 	if err != nil {
 		log.Fatal(err)
 	}
+	sqlInstance = dbinst
+	Outbox.DB = dbinst
 	// Based on the driver create the corresponding DB instance.
 	// By default, the SuperKit boilerplate comes with a pre-configured
 	// ORM called Gorm. https://gorm.io.
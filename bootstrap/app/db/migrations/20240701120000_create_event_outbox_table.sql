@@ -0,0 +1,12 @@
+-- +goose Up
+create table if not exists event_outbox(
+	id text primary key,
+	topic text not null,
+	payload text not null,
+	created_at datetime not null,
+	processed_at datetime
+);
+create index if not exists event_outbox_processed_at on event_outbox(processed_at);
+
+-- +goose Down
+drop table if exists event_outbox;
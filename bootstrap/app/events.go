@@ -3,8 +3,6 @@ package app
 import (
 	"AABBCCDD/app/events"
 	"AABBCCDD/plugins/auth"
-
-	"github.com/anthdm/superkit/event"
 )
 
 // Events are functions that are handled in separate goroutines.
@@ -13,9 +11,16 @@ import (
 // - sending email
 // - sending notifications (Slack, Telegram, Discord)
 // - analytics..
+//
+// Topics are typed with event.NewTopic, hence handlers receive their
+// payload without type assertions.
+//
+// Handlers that return an error (or panic) are retried based on the
+// event.DefaultRetryPolicy. Events that still fail are kept as dead
+// letters, see event.DeadLetters() and event.Replay(id).
 
 // Register your events here.
 func RegisterEvents() {
-	event.Subscribe(auth.UserSignupEvent, events.OnUserSignup)
-	event.Subscribe(auth.ResendVerificationEvent, events.OnResendVerificationToken)
+	auth.UserSignupEvent.Subscribe(events.OnUserSignup)
+	auth.ResendVerificationEvent.Subscribe(events.OnResendVerificationToken)
 }
@@ -3,25 +3,35 @@ package events
 import (
 	"AABBCCDD/plugins/auth"
 	"context"
-	"encoding/json"
 	"fmt"
+	"net/url"
+
+	"github.com/anthdm/superkit/kit"
+	"github.com/anthdm/superkit/kit/mail"
 )
 
 // Event handlers
-func OnUserSignup(ctx context.Context, event any) {
-	userWithToken, ok := event.(auth.UserWithVerificationToken)
-	if !ok {
-		return
-	}
-	b, _ := json.MarshalIndent(userWithToken, "   ", "    ")
-	fmt.Println(string(b))
+//
+// Returning an error retries the handler, once all attempts failed
+// the event ends up in the event.DeadLetterTopic.
+func OnUserSignup(ctx context.Context, userWithToken auth.UserWithVerificationToken) error {
+	return sendVerificationEmail(ctx, userWithToken)
+}
+
+func OnResendVerificationToken(ctx context.Context, userWithToken auth.UserWithVerificationToken) error {
+	return sendVerificationEmail(ctx, userWithToken)
 }
 
-func OnResendVerificationToken(ctx context.Context, event any) {
-	userWithToken, ok := event.(auth.UserWithVerificationToken)
-	if !ok {
-		return
+func sendVerificationEmail(ctx context.Context, userWithToken auth.UserWithVerificationToken) error {
+	appURL := kit.Getenv("SUPERKIT_APP_URL", "http://localhost:3000")
+	link := fmt.Sprintf("%s/email/verify?token=%s", appURL, url.QueryEscape(userWithToken.Token))
+
+	msg := mail.NewMessage(userWithToken.User.Email, "Verify your email address")
+	if err := msg.Render(ctx, auth.VerifyEmail(userWithToken.User, link)); err != nil {
+		return fmt.Errorf("failed to render verification email: %w", err)
+	}
+	if err := mail.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send verification email to %s: %w", userWithToken.User.Email, err)
 	}
-	b, _ := json.MarshalIndent(userWithToken, "   ", "    ")
-	fmt.Println(string(b))
+	return nil
 }
@@ -1,12 +1,16 @@
 package app
 
 import (
+	"AABBCCDD/app/db"
 	"AABBCCDD/app/handlers"
 	"AABBCCDD/app/views/errors"
 	"AABBCCDD/plugins/auth"
 	"log/slog"
 
 	"github.com/anthdm/superkit/kit"
+	"github.com/anthdm/superkit/kit/dashboard"
+	"github.com/anthdm/superkit/kit/health"
+	"github.com/anthdm/superkit/kit/metrics"
 	"github.com/anthdm/superkit/kit/middleware"
 	"github.com/go-chi/chi/v5"
 
@@ -17,11 +21,34 @@ import (
 func InitializeMiddleware(router *chi.Mux) {
 	router.Use(chimiddleware.Logger)
 	router.Use(chimiddleware.Recoverer)
+	router.Use(metrics.Middleware)
 	router.Use(middleware.WithRequest)
+	router.Use(middleware.WithTracing) // only traces when an exporter is configured
+	router.Use(dashboard.Recorder)     // only records in development
+
+	// Multi tenancy
+	//
+	// Resolve the tenant of every request from its subdomain and load
+	// it into the request context (kit.Tenant(), view.Tenant(ctx)).
+	//  router.Use(tenant.Middleware(tenant.Config{
+	//      Resolver: tenant.FromSubdomain("example.com"),
+	//      Store:    tenant.SQLStore{DB: sqlDB},
+	//  }))
 }
 
 // Define your routes in here
 func InitializeRoutes(router *chi.Mux) {
+	// Health and metrics
+	//
+	// /healthz is the liveness endpoint, /readyz runs all the registered
+	// readiness checks and /metrics exposes Prometheus metrics.
+	sqlDB, _ := db.Get().DB()
+	health.Register("db", health.PingDB(sqlDB))
+	metrics.RegisterDB("default", sqlDB)
+	router.Get("/healthz", health.Healthz)
+	router.Get("/readyz", health.Readyz)
+	router.Handle("/metrics", metrics.Handler())
+
 	// Authentication plugin
 	//
 	// By default the auth plugin is active, to disable the auth plugin
@@ -56,6 +83,14 @@ func InitializeRoutes(router *chi.Mux) {
 		// Routes
 		// app.Get("/path", kit.Handler(myHandler.HandleIndex))
 	})
+
+	// Development dashboard
+	//
+	// Only mounted in development at /_superkit. It shows all routes,
+	// event topics, recent requests, migrations and the environment.
+	dashboard.Mount(router, dashboard.Config{
+		DB: sqlDB,
+	})
 }
 
 // NotFoundHandler that will be called when the requested path could
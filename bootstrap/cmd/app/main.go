@@ -2,19 +2,32 @@ package main
 
 import (
 	"AABBCCDD/app"
+	"AABBCCDD/app/db"
 	"AABBCCDD/public"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 
 	"github.com/anthdm/superkit/kit"
+	"github.com/anthdm/superkit/kit/mail"
+	"github.com/anthdm/superkit/kit/trace"
 	"github.com/go-chi/chi/v5"
 	"github.com/joho/godotenv"
 )
 
 func main() {
 	kit.Setup()
+
+	exporter, err := trace.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if exporter != nil {
+		trace.UseExporter(exporter)
+	}
+
 	router := chi.NewMux()
 
 	app.InitializeMiddleware(router)
@@ -29,8 +42,20 @@ func main() {
 	router.HandleFunc("/*", kit.Handler(app.NotFoundHandler))
 
 	app.InitializeRoutes(router)
+
+	mailer, err := mail.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	mail.Use(mailer)
+
 	app.RegisterEvents()
 
+	// Relay the events stored in the outbox to their subscribers. The
+	// relay starts once the mailer and the handlers are in place, as it
+	// picks up the events a crash left pending right away.
+	go db.Outbox.Relay(context.Background())
+
 	listenAddr := os.Getenv("HTTP_LISTEN_ADDR")
 	// In development link the full Templ proxy url.
 	url := "http://localhost:7331"
@@ -41,7 +41,7 @@ func HandleLoginCreate(kit *kit.Kit) error {
 	}
 
 	var user User
-	err := db.Get().Find(&user, "email = ?", values.Email).Error
+	err := db.Get().WithContext(kit.Request.Context()).Find(&user, "email = ?", values.Email).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			errors.Add("credentials", "invalid credentials")
@@ -73,7 +73,7 @@ func HandleLoginCreate(kit *kit.Kit) error {
 		Token:     uuid.New().String(),
 		ExpiresAt: time.Now().Add(time.Hour * time.Duration(sessionExpiry)),
 	}
-	if err = db.Get().Create(&session).Error; err != nil {
+	if err = db.Get().WithContext(kit.Request.Context()).Create(&session).Error; err != nil {
 		return err
 	}
 
@@ -91,7 +91,7 @@ func HandleLoginDelete(kit *kit.Kit) error {
 		sess.Values = map[any]any{}
 		sess.Save(kit.Request, kit.Response)
 	}()
-	err := db.Get().Delete(&Session{}, "token = ?", sess.Values["sessionToken"]).Error
+	err := db.Get().WithContext(kit.Request.Context()).Delete(&Session{}, "token = ?", sess.Values["sessionToken"]).Error
 	if err != nil {
 		return err
 	}
@@ -129,7 +129,7 @@ func HandleEmailVerify(kit *kit.Kit) error {
 	}
 
 	var user User
-	err = db.Get().First(&user, userID).Error
+	err = db.Get().WithContext(kit.Request.Context()).First(&user, userID).Error
 	if err != nil {
 		return err
 	}
@@ -140,7 +140,7 @@ func HandleEmailVerify(kit *kit.Kit) error {
 
 	now := sql.NullTime{Time: time.Now(), Valid: true}
 	user.EmailVerifiedAt = now
-	err = db.Get().Save(&user).Error
+	err = db.Get().WithContext(kit.Request.Context()).Save(&user).Error
 	if err != nil {
 		return err
 	}
@@ -157,7 +157,7 @@ func AuthenticateUser(kit *kit.Kit) (kit.Auth, error) {
 	}
 
 	var session Session
-	err := db.Get().
+	err := db.Get().WithContext(kit.Request.Context()).
 		Preload("User").
 		Find(&session, "token = ? AND expires_at > ?", token, time.Now()).Error
 	if err != nil || session.ID == 0 {
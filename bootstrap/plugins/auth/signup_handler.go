@@ -8,22 +8,15 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/anthdm/superkit/event"
 	"github.com/anthdm/superkit/kit"
 	v "github.com/anthdm/superkit/validate"
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
 var signupSchema = v.Schema{
-	"email": v.Rules(v.Email),
-	"password": v.Rules(
-		v.ContainsSpecial,
-		v.ContainsUpper,
-		v.Min(7),
-		v.Max(50),
-	),
-	"firstName": v.Rules(v.Min(2), v.Max(50)),
-	"lastName":  v.Rules(v.Min(2), v.Max(50)),
+	"email":           v.Rules(v.Unique(db.SQL(), "users", "email").Message("email is already in use")),
+	"passwordConfirm": v.Rules(v.EqualField("password").Message("passwords do not match")),
 }
 
 func HandleSignupIndex(kit *kit.Kit) error {
@@ -36,22 +29,28 @@ func HandleSignupCreate(kit *kit.Kit) error {
 	if !ok {
 		return kit.Render(SignupForm(values, errors))
 	}
-	if values.Password != values.PasswordConfirm {
-		errors.Add("passwordConfirm", "passwords do not match")
-		return kit.Render(SignupForm(values, errors))
-	}
-	user, err := createUserFromFormValues(values)
-	if err != nil {
-		return err
-	}
-	token, err := createVerificationToken(user.ID)
+	// The signup event is stored in the outbox within the same transaction
+	// as the user, hence the verification email is sent even if the
+	// process dies right after the commit.
+	var user User
+	err := db.Get().WithContext(kit.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		var err error
+		user, err = createUserFromFormValues(tx, values)
+		if err != nil {
+			return err
+		}
+		token, err := createVerificationToken(user.ID)
+		if err != nil {
+			return err
+		}
+		return UserSignupEvent.EmitOutbox(kit.Request.Context(), db.Outbox, tx.Statement.ConnPool, UserWithVerificationToken{
+			Token: token,
+			User:  user,
+		})
+	})
 	if err != nil {
 		return err
 	}
-	event.Emit(UserSignupEvent, UserWithVerificationToken{
-		Token: token,
-		User:  user,
-	})
 	return kit.Render(ConfirmEmail(user))
 }
 
@@ -63,7 +62,7 @@ func HandleResendVerificationCode(kit *kit.Kit) error {
 	}
 
 	var user User
-	if err = db.Get().First(&user, id).Error; err != nil {
+	if err = db.Get().WithContext(kit.Request.Context()).First(&user, id).Error; err != nil {
 		return kit.Text(http.StatusOK, "An unexpected error occured")
 	}
 
@@ -76,10 +75,13 @@ func HandleResendVerificationCode(kit *kit.Kit) error {
 		return kit.Text(http.StatusOK, "An unexpected error occured")
 	}
 
-	event.Emit(ResendVerificationEvent, UserWithVerificationToken{
+	err = ResendVerificationEvent.Emit(kit.Request.Context(), UserWithVerificationToken{
 		User:  user,
 		Token: token,
 	})
+	if err != nil {
+		return kit.Text(http.StatusOK, "An unexpected error occured")
+	}
 
 	msg := fmt.Sprintf("A new verification token has been sent to %s", user.Email)
 
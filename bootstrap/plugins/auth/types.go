@@ -1,18 +1,18 @@
 package auth
 
 import (
-	"AABBCCDD/app/db"
 	"database/sql"
 	"time"
 
+	"github.com/anthdm/superkit/event"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
-// Event name constants
-const (
-	UserSignupEvent         = "auth.signup"
-	ResendVerificationEvent = "auth.resend.verification"
+// Event topics
+var (
+	UserSignupEvent         = event.NewTopic[UserWithVerificationToken]("auth.signup")
+	ResendVerificationEvent = event.NewTopic[UserWithVerificationToken]("auth.resend.verification")
 )
 
 // UserWithVerificationToken is a struct that will be sent over the
@@ -44,7 +44,7 @@ type User struct {
 	UpdatedAt       time.Time
 }
 
-func createUserFromFormValues(values SignupFormValues) (User, error) {
+func createUserFromFormValues(tx *gorm.DB, values SignupFormValues) (User, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(values.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return User{}, err
@@ -55,7 +55,7 @@ func createUserFromFormValues(values SignupFormValues) (User, error) {
 		LastName:     values.LastName,
 		PasswordHash: string(hash),
 	}
-	result := db.Get().Create(&user)
+	result := tx.Create(&user)
 	return user, result.Error
 }
 
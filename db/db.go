@@ -3,6 +3,8 @@ package db
 import (
 	"database/sql"
 	"fmt"
+
+	"github.com/anthdm/superkit/kit/trace"
 )
 
 const (
@@ -16,6 +18,8 @@ type Config struct {
 	Host     string
 	User     string
 	Password string
+	// Trace records every database call as a span, see the kit/trace package.
+	Trace bool
 }
 
 func NewSQL(cfg Config) (*sql.DB, error) {
@@ -25,6 +29,9 @@ func NewSQL(cfg Config) (*sql.DB, error) {
 		if len(name) == 0 {
 			name = "app_db"
 		}
+		if cfg.Trace {
+			return trace.OpenDB(cfg.Driver, name)
+		}
 		return sql.Open(cfg.Driver, name)
 	default:
 		return nil, fmt.Errorf("invalid database driver (%s): currently only sqlite3 is supported", cfg.Driver)
@@ -0,0 +1,584 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/anthdm/superkit/kit/trace"
+)
+
+// Ordering configures which events a Bus delivers in the order they
+// were emitted.
+type Ordering int
+
+const (
+	// Unordered delivers events to any free worker.
+	Unordered Ordering = iota
+	// OrderByTopic delivers all events of the same topic in order.
+	OrderByTopic
+	// OrderByKey delivers all events with the same partition key in order.
+	// The partition key of an event implementing Keyer is its EventKey,
+	// other events are partitioned by topic.
+	OrderByKey
+)
+
+// Keyer is implemented by events that have a partition key, like the ID
+// of the user they belong to. See OrderByKey.
+type Keyer interface {
+	EventKey() string
+}
+
+const (
+	defaultBufferSize = 128
+	defaultWorkers    = 32
+)
+
+// Options configures a Bus.
+type Options struct {
+	// BufferSize is the number of emitted events that can be queued
+	// before the overflow policy applies. Defaults to 128.
+	BufferSize int
+	// Workers is the number of goroutines handling events. Defaults to 32.
+	Workers int
+	// Ordering configures which events are delivered in order. Ordered
+	// events are handled one after another by the same worker, hence a
+	// slow or retrying handler delays the events of its partition.
+	Ordering Ordering
+	// Retry is the retry policy for subscriptions without their own
+	// policy. Defaults to DefaultRetryPolicy.
+	Retry *RetryPolicy
+	// Transport delivers events to the buses of other processes. Defaults
+	// to delivering events in memory to this Bus only.
+	Transport Transport
+	// Codec encodes the payloads sent over the Transport. Defaults to
+	// JSONCodec.
+	Codec Codec
+	// Synchronous handles every event inline before Emit returns, which
+	// makes tests deterministic. Workers, BufferSize, Ordering and
+	// Transport are ignored.
+	Synchronous bool
+	// HistorySize is the number of events kept per topic, see KeepHistory.
+	HistorySize int
+	// HistoryMaxAge is the maximum age of the events kept, see KeepHistory.
+	HistoryMaxAge time.Duration
+	// Overflow is the policy for events emitted while the queue is full,
+	// for topics without their own policy, see SetOverflow. Defaults to
+	// Block.
+	Overflow OverflowPolicy
+	// OverflowSize is the number of events kept per topic by DropOldest.
+	// Defaults to BufferSize.
+	OverflowSize int
+	// SpillDir is the directory SpillToDisk writes to. Defaults to a
+	// directory in os.TempDir.
+	SpillDir string
+}
+
+// Bus dispatches emitted events to its subscribers with a bounded pool
+// of workers. The package level functions use the default Bus.
+type Bus struct {
+	mu         sync.RWMutex
+	subs       *node
+	retry      RetryPolicy
+	hooks      []Hooks
+	middleware []Middleware
+	stats      stats
+
+	// closemu guards closed, senders register with sending under it so
+	// Stop only closes queue once they are done. stopping is closed when
+	// Stop starts, to release the senders waiting for room.
+	closemu  sync.RWMutex
+	closed   bool
+	sending  sync.WaitGroup
+	stopping chan struct{}
+	stopOnce sync.Once
+
+	ordering    Ordering
+	synchronous bool
+	transport   Transport
+	codec       Codec
+	queue       chan event
+	workers     []chan delivery
+	wg          sync.WaitGroup
+	deadLetters deadLetters
+
+	schedmu   sync.Mutex
+	scheduled map[string]*Scheduled
+	history   history
+
+	defaultOverflow OverflowPolicy
+	overflowRules   []overflowRule
+	overflow        overflow
+}
+
+// delivery is an event on its way to a single subscription.
+type delivery struct {
+	sub Subscription
+	evt event
+}
+
+// NewBus returns a new Bus that is ready to use.
+func NewBus(opts Options) *Bus {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = defaultBufferSize
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = defaultWorkers
+	}
+	if opts.OverflowSize <= 0 {
+		opts.OverflowSize = opts.BufferSize
+	}
+	if len(opts.SpillDir) == 0 {
+		opts.SpillDir = filepath.Join(os.TempDir(), "superkit-events")
+	}
+	b := &Bus{
+		subs:        newNode(),
+		retry:       DefaultRetryPolicy,
+		ordering:    opts.Ordering,
+		synchronous: opts.Synchronous,
+		transport:   opts.Transport,
+		codec:       opts.Codec,
+		queue:       make(chan event, opts.BufferSize),
+		stopping:    make(chan struct{}),
+
+		defaultOverflow: opts.Overflow,
+		overflow:        newOverflow(opts.OverflowSize, opts.SpillDir),
+	}
+	if opts.Retry != nil {
+		b.retry = *opts.Retry
+	}
+	if b.codec == nil {
+		b.codec = JSONCodec
+	}
+	b.KeepHistory(opts.HistorySize, opts.HistoryMaxAge)
+	if b.synchronous {
+		b.transport = nil
+		return b
+	}
+	// Unordered workers share a single channel, ordered workers each own
+	// a channel so a partition always ends up at the same worker.
+	shared := make(chan delivery, opts.Workers)
+	for range opts.Workers {
+		ch := shared
+		if b.ordering != Unordered {
+			ch = make(chan delivery, opts.BufferSize/opts.Workers+1)
+		}
+		b.workers = append(b.workers, ch)
+		b.wg.Add(1)
+		go b.work(ch)
+	}
+	b.wg.Add(1)
+	go b.dispatch()
+	go b.drain()
+	if b.transport != nil {
+		b.wg.Add(1)
+		go b.receive()
+	}
+	return b
+}
+
+// Emit emits an event to the given topic. Handlers receive a context
+// carrying the values of ctx registered with PropagateValues, but
+// detached from its cancellation.
+//
+// While the queue is full the overflow policy of the topic applies, see
+// SetOverflow. With the default Block policy Emit waits for room until
+// ctx is done, in which case the event is dropped and ctx.Err() returned.
+// Events emitted after Stop, or still waiting for room when it is
+// called, are dropped with ErrStopped.
+//
+// With a Transport the event is encoded and published to all processes
+// and the overflow policy applies to the received events instead.
+func (b *Bus) Emit(ctx context.Context, topic string, v any) error {
+	return b.emit(ctx, topic, v, true)
+}
+
+// TryEmit emits an event like Emit but never blocks. It returns false if
+// the event was dropped, which with the Block policy is the case while
+// the queue is full.
+func (b *Bus) TryEmit(ctx context.Context, topic string, v any) bool {
+	return b.emit(ctx, topic, v, false) == nil
+}
+
+func (b *Bus) emit(ctx context.Context, topic string, v any, wait bool) error {
+	b.emitted(topic)
+	if b.synchronous {
+		b.closemu.RLock()
+		closed := b.closed
+		b.closemu.RUnlock()
+		if closed {
+			return ErrStopped
+		}
+		evt := event{ctx: detach(ctx), topic: topic, message: v}
+		b.record(evt)
+		for _, sub := range b.match(topic) {
+			b.handle(sub, evt)
+		}
+		return nil
+	}
+	if b.transport != nil {
+		// Publishing doesn't register with sending, Stop closes the
+		// transport to end publishes still in progress.
+		b.closemu.RLock()
+		closed := b.closed
+		b.closemu.RUnlock()
+		if closed {
+			return ErrStopped
+		}
+		return b.publish(ctx, topic, v)
+	}
+	return b.enqueue(ctx, event{
+		ctx:     detach(ctx),
+		topic:   topic,
+		message: v,
+	}, wait)
+}
+
+func (b *Bus) publish(ctx context.Context, topic string, v any) error {
+	payload, err := b.codec.Encode(v)
+	if err != nil {
+		return err
+	}
+	env := Envelope{
+		ID:      newEventID(),
+		Topic:   topic,
+		Payload: payload,
+	}
+	if span := trace.SpanFromContext(ctx); span != nil {
+		env.Traceparent = span.Context.Traceparent()
+	}
+	return b.transport.Publish(ctx, env)
+}
+
+// receive enqueues the events received from the transport until it is closed.
+func (b *Bus) receive() {
+	defer b.wg.Done()
+	for env := range b.transport.Receive() {
+		v, err := decodePayload(b.codec, env.Topic, env.Payload)
+		if err != nil {
+			b.deadLettered(b.deadLetters.add(DeadLetter{Topic: env.Topic, Message: env.Payload, Err: err, FailedAt: time.Now()}))
+			slog.Error("event: failed to decode received payload", "topic", env.Topic, "id", env.ID, "err", err)
+			continue
+		}
+		ctx := context.WithValue(context.Background(), eventIDKey{}, env.ID)
+		if sc, err := trace.ParseTraceparent(env.Traceparent); err == nil {
+			ctx = trace.ContextWithRemote(ctx, sc)
+		}
+		if err := b.enqueue(ctx, event{ctx: ctx, topic: env.Topic, message: v}, true); err != nil {
+			slog.Warn("event: dropped received event", "topic", env.Topic, "id", env.ID, "err", err)
+		}
+	}
+}
+
+// EmitSync calls the handlers subscribed to the topic one after another
+// and returns once they are done, see EmitSync.
+func (b *Bus) EmitSync(ctx context.Context, topic string, v any) error {
+	b.emitted(topic)
+	evt := event{ctx: ctx, topic: topic, message: v}
+	b.record(event{ctx: detach(ctx), topic: topic, message: v})
+	var errs []error
+	for _, sub := range b.match(topic) {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if _, err := b.run(sub, evt); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.Topic, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Subscribe subscribes a HandlerFunc to the given topic, see Subscribe.
+func (b *Bus) Subscribe(topic string, h HandlerFunc, opts ...SubscribeOption) Subscription {
+	sub := Subscription{
+		CreatedAt: time.Now().UnixNano(),
+		Topic:     topic,
+		Fn:        h,
+	}
+	for _, opt := range opts {
+		opt(&sub)
+	}
+
+	b.mu.Lock()
+	b.subs.insert(sub)
+	b.mu.Unlock()
+
+	if sub.replay > 0 {
+		b.replay(sub)
+	}
+	return sub
+}
+
+// Unsubscribe unsubscribes the given Subscription from its topic.
+func (b *Bus) Unsubscribe(sub Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs.remove(sub, splitTopic(sub.Topic))
+}
+
+// Topics returns all the topics, or topic patterns, that have subscribers
+// together with their number of subscribers.
+func (b *Bus) Topics() map[string]int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	topics := make(map[string]int)
+	b.subs.walk(nil, func(pattern string, subs []Subscription) {
+		topics[pattern] = len(subs)
+	})
+	return topics
+}
+
+// Subscribers returns the number of subscriptions an event emitted to the
+// given topic would be delivered to, including wildcard subscriptions.
+func (b *Bus) Subscribers(topic string) int {
+	return len(b.match(topic))
+}
+
+// QueueDepth returns the number of emitted events waiting to be handled.
+func (b *Bus) QueueDepth() int {
+	n := len(b.queue) + b.overflow.len()
+	if len(b.workers) == 0 {
+		return n
+	}
+	if b.ordering == Unordered {
+		return n + len(b.workers[0])
+	}
+	for _, ch := range b.workers {
+		n += len(ch)
+	}
+	return n
+}
+
+// OnHandled registers a function that is called each time a subscriber
+// finished handling an event, with the topic and the handling duration.
+func (b *Bus) OnHandled(fn func(topic string, d time.Duration)) {
+	b.AddHooks(Hooks{OnHandled: func(topic string, d time.Duration, _ error) {
+		fn(topic, d)
+	}})
+}
+
+// Use adds middleware that wraps the handlers of all subscriptions,
+// except the ones subscribed WithoutMiddleware. The first middleware
+// is the outermost.
+func (b *Bus) Use(mw ...Middleware) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.middleware = append(b.middleware, mw...)
+}
+
+// UseRetryPolicy sets the retry policy used for subscriptions without
+// their own policy.
+func (b *Bus) UseRetryPolicy(p RetryPolicy) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.retry = p
+}
+
+// Stop stops accepting new events and returns once all queued events,
+// including the ones in the overflow, have been handled. Pending scheduled events are dropped and the
+// Transport of the Bus is closed.
+func (b *Bus) Stop() {
+	b.cancelAllScheduled()
+	b.stopOnce.Do(func() { close(b.stopping) })
+	b.closemu.Lock()
+	if b.closed {
+		b.closemu.Unlock()
+		return
+	}
+	b.closed = true
+	b.closemu.Unlock()
+	if b.synchronous {
+		return
+	}
+	// Handlers may still be emitting, the workers keep running until the
+	// queue is closed, so the senders always get to finish.
+	b.sending.Wait()
+	close(b.overflow.quit)
+	<-b.overflow.done
+	close(b.queue)
+	if b.transport != nil {
+		if err := b.transport.Close(); err != nil {
+			slog.Error("event: failed to close transport", "err", err)
+		}
+	}
+	b.wg.Wait()
+}
+
+func (b *Bus) match(topic string) []Subscription {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.subs.match(splitTopic(topic), nil)
+}
+
+func (b *Bus) isSubscribed(sub Subscription) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	n := b.subs.find(sub.Topic)
+	return n != nil && slices.ContainsFunc(n.subs, func(s Subscription) bool {
+		return s.CreatedAt == sub.CreatedAt
+	})
+}
+
+// dispatch hands every queued event to the workers until the queue is
+// closed, after which it closes the worker channels.
+func (b *Bus) dispatch() {
+	defer b.wg.Done()
+	for evt := range b.queue {
+		subs := b.match(evt.topic)
+		depth := &b.stats.topic(evt.topic).queueDepth
+		depth.Add(int64(len(subs)) - 1)
+		for _, sub := range subs {
+			b.worker(evt) <- delivery{sub: sub, evt: evt}
+		}
+	}
+	if b.ordering == Unordered {
+		close(b.workers[0])
+		return
+	}
+	for _, ch := range b.workers {
+		close(ch)
+	}
+}
+
+// worker returns the channel of the worker responsible for the event.
+func (b *Bus) worker(evt event) chan delivery {
+	var key string
+	switch b.ordering {
+	case Unordered:
+		return b.workers[0]
+	case OrderByKey:
+		key = evt.topic
+		if k, ok := evt.message.(Keyer); ok {
+			key = k.EventKey()
+		}
+	default:
+		key = evt.topic
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return b.workers[h.Sum32()%uint32(len(b.workers))]
+}
+
+// run calls the handler of the subscription until it succeeds, it ran out
+// of attempts or the context of the event is done. It returns the number
+// of attempts and the error of the last one.
+func (b *Bus) run(sub Subscription, evt event) (int, error) {
+	hooks := b.allHooks()
+	globalmu.RLock()
+	global := globalMiddleware
+	globalmu.RUnlock()
+	b.mu.RLock()
+	policy := b.retry
+	middleware := b.middleware
+	b.mu.RUnlock()
+
+	h := chain(sub.Fn, sub.middleware)
+	if !sub.skipMiddleware {
+		h = chain(h, global, middleware)
+	}
+	if sub.retry != nil {
+		policy = *sub.retry
+	}
+
+	var (
+		err      error
+		attempts int
+	)
+	for attempts < max(policy.MaxAttempts, 1) {
+		if attempts > 0 {
+			select {
+			case <-time.After(policy.delay(attempts)):
+			case <-evt.ctx.Done():
+				return attempts, errors.Join(err, evt.ctx.Err())
+			}
+		}
+		attempts++
+
+		ctx, span := trace.Start(context.WithValue(evt.ctx, topicKey{}, evt.topic), "event "+evt.topic,
+			trace.WithKind(trace.KindConsumer),
+			trace.WithAttributes("event.topic", evt.topic, "event.subscription", sub.Topic, "event.attempt", attempts),
+		)
+		start := time.Now()
+		err = call(h, ctx, evt.message)
+		d := time.Since(start)
+		span.RecordError(err)
+		span.End()
+
+		b.handled(evt.topic, d, err, hooks)
+		if err == nil {
+			return attempts, nil
+		}
+		slog.Warn("event handler failed", "topic", evt.topic, "attempt", attempts, "err", err)
+	}
+	return attempts, err
+}
+
+// deliver handles the event with all matching subscriptions and returns
+// once they are done, bypassing the queue and the workers.
+func (b *Bus) deliver(evt event) {
+	b.record(evt)
+	var wg sync.WaitGroup
+	for _, sub := range b.match(evt.topic) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.handle(sub, evt)
+		}()
+	}
+	wg.Wait()
+}
+
+func (b *Bus) work(ch chan delivery) {
+	defer b.wg.Done()
+	for d := range ch {
+		b.stats.topic(d.evt.topic).queueDepth.Add(-1)
+		b.handle(d.sub, d.evt)
+	}
+}
+
+// handle delivers the event to the subscriber, retrying it according to
+// the retry policy of the subscription. Once all attempts failed, the
+// event is dead lettered.
+func (b *Bus) handle(sub Subscription, evt event) {
+	attempts, err := b.run(sub, evt)
+	if err == nil {
+		return
+	}
+
+	if evt.topic == DeadLetterTopic {
+		slog.Error("dead letter handler failed", "err", err)
+		return
+	}
+	dl := b.deadLetters.add(DeadLetter{
+		Topic:    evt.topic,
+		Message:  evt.message,
+		Err:      err,
+		Attempts: attempts,
+		FailedAt: time.Now(),
+		sub:      sub,
+		evt:      evt,
+	})
+	b.deadLettered(dl)
+	slog.Error("event dead lettered", "topic", evt.topic, "attempts", attempts, "err", err, "id", dl.ID)
+
+	// Dead letters are handled right away by the current worker, queueing
+	// them could deadlock when the queue is full.
+	dlevt := event{ctx: evt.ctx, topic: DeadLetterTopic, message: dl}
+	for _, sub := range b.match(DeadLetterTopic) {
+		// Catch-all subscribers expect the payloads of the application.
+		if sub.Topic == wildcardTail {
+			continue
+		}
+		b.handle(sub, dlevt)
+	}
+}
@@ -0,0 +1,135 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type keyed struct {
+	key string
+	seq int
+}
+
+func (k keyed) EventKey() string { return k.key }
+
+func TestBusOrderByKey(t *testing.T) {
+	bus := NewBus(Options{Workers: 4, Ordering: OrderByKey})
+
+	var (
+		mu   sync.Mutex
+		seen = map[string][]int{}
+	)
+	bus.Subscribe("orders", func(_ context.Context, event any) error {
+		k := event.(keyed)
+		// Give other events the chance to overtake this one.
+		time.Sleep(time.Duration(k.seq%3) * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		seen[k.key] = append(seen[k.key], k.seq)
+		return nil
+	})
+	for seq := range 20 {
+		for key := range 3 {
+			bus.Emit(context.Background(), "orders", keyed{key: strconv.Itoa(key), seq: seq})
+		}
+	}
+	bus.Stop()
+
+	for key, seqs := range seen {
+		if len(seqs) != 20 {
+			t.Errorf("key %s: expected 20 events got %d", key, len(seqs))
+		}
+		for i, seq := range seqs {
+			if seq != i {
+				t.Fatalf("key %s: expected events in order got %v", key, seqs)
+			}
+		}
+	}
+}
+
+func TestBusStopDrains(t *testing.T) {
+	bus := NewBus(Options{BufferSize: 64, Workers: 2})
+	var (
+		mu      sync.Mutex
+		handled int
+	)
+	bus.Subscribe("foo", func(context.Context, any) error {
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		handled++
+		return nil
+	})
+	for i := range 50 {
+		bus.Emit(context.Background(), "foo", i)
+	}
+	bus.Stop()
+	if handled != 50 {
+		t.Errorf("expected 50 handled events got %d", handled)
+	}
+	if depth := bus.QueueDepth(); depth != 0 {
+		t.Errorf("expected empty queue got %d", depth)
+	}
+
+	// Emitting on, or stopping, a stopped bus is a noop.
+	if err := bus.Emit(context.Background(), "foo", 51); !errors.Is(err, ErrStopped) {
+		t.Errorf("expected ErrStopped got %v", err)
+	}
+	bus.Stop()
+}
+
+func TestBusStopWhileHandlersEmit(t *testing.T) {
+	bus := NewBus(Options{BufferSize: 1, Workers: 1})
+	release := make(chan struct{})
+	bus.Subscribe("a", func(ctx context.Context, v any) error {
+		<-release
+		bus.Emit(ctx, "b", v)
+		return nil
+	})
+	// Fill the queue with emitters waiting for room.
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bus.Emit(context.Background(), "a", i)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		bus.Stop()
+		wg.Wait()
+		close(stopped)
+	}()
+	// The handlers emit once Stop is underway.
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop deadlocked with handlers emitting on a full queue")
+	}
+}
+
+func TestBusConcurrentSubscribe(t *testing.T) {
+	bus := NewBus(Options{})
+	defer bus.Stop()
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sub := bus.Subscribe("foo."+strconv.Itoa(i), func(context.Context, any) error { return nil })
+			bus.Emit(context.Background(), sub.Topic, i)
+			bus.Unsubscribe(sub)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,75 @@
+package event
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+// Codec encodes and decodes the payloads of events that leave the
+// process, through an Outbox or a Transport.
+type Codec interface {
+	Encode(v any) ([]byte, error)
+	Decode(data []byte, v any) error
+}
+
+// JSONCodec encodes payloads as JSON. It is the default codec.
+var JSONCodec Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(v any) ([]byte, error)    { return json.Marshal(v) }
+func (jsonCodec) Decode(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+var (
+	payloadmu    sync.RWMutex
+	payloadTypes = map[string]reflect.Type{}
+)
+
+// RegisterPayload registers the type the encoded payloads of the given
+// topic are decoded into. Typed topics created with NewTopic are
+// registered automatically. Payloads of unregistered topics are
+// delivered as json.RawMessage by the JSONCodec and as []byte by
+// other codecs.
+func RegisterPayload[T any](topic string) {
+	payloadmu.Lock()
+	defer payloadmu.Unlock()
+	payloadTypes[topic] = reflect.TypeFor[T]()
+}
+
+func decodePayload(codec Codec, topic string, payload []byte) (any, error) {
+	payloadmu.RLock()
+	typ, ok := payloadTypes[topic]
+	payloadmu.RUnlock()
+	if !ok {
+		if codec == JSONCodec {
+			return json.RawMessage(payload), nil
+		}
+		return payload, nil
+	}
+	v := reflect.New(typ)
+	if err := codec.Decode(payload, v.Interface()); err != nil {
+		return nil, err
+	}
+	return v.Elem().Interface(), nil
+}
+
+type eventIDKey struct{}
+
+// EventID returns the ID of the event being handled, which is only set for
+// events relayed from an Outbox or received through a Transport. The ID
+// is the same for every delivery of an event and can be used to
+// deduplicate them.
+func EventID(ctx context.Context) string {
+	id, _ := ctx.Value(eventIDKey{}).(string)
+	return id
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,51 @@
+package event
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/anthdm/superkit/kit/trace"
+)
+
+var (
+	propagatemu sync.RWMutex
+	propagated  []any
+)
+
+// PropagateValues registers context keys whose values are carried from
+// the context passed to EmitContext to the handlers. Other values, like
+// the *http.Request of the handler that emitted the event, are dropped,
+// since they are only valid while the request is being served.
+//
+//	event.PropagateValues(middleware.RequestIDKey)
+//
+// The current trace span is always carried, the auth of kit and the tenant
+// of kit/tenant register their keys themselves.
+func PropagateValues(keys ...any) {
+	propagatemu.Lock()
+	defer propagatemu.Unlock()
+	for _, key := range keys {
+		if !slices.Contains(propagated, key) {
+			propagated = append(propagated, key)
+		}
+	}
+}
+
+// detach returns a context that holds the propagated values of ctx but
+// is detached from its cancellation and deadline, since handlers of
+// asynchronous events run after the caller might have returned.
+func detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	if span := trace.SpanFromContext(ctx); span != nil {
+		detached = trace.ContextWithRemote(detached, span.Context)
+	}
+	propagatemu.RLock()
+	defer propagatemu.RUnlock()
+	for _, key := range propagated {
+		if v := ctx.Value(key); v != nil {
+			detached = context.WithValue(detached, key, v)
+		}
+	}
+	return detached
+}
@@ -0,0 +1,98 @@
+package event
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DeadLetterTopic is the topic a DeadLetter is emitted to when a handler
+// exhausted all its attempts. Failures of its own subscribers are logged
+// but never dead lettered again. Dead letters are not delivered to the
+// catch-all ">" subscriptions, subscribe to the topic itself or a pattern
+// like "event.>".
+const DeadLetterTopic = "event.deadletter"
+
+// maxDeadLetters is the number of dead letters kept in memory.
+const maxDeadLetters = 1000
+
+// DeadLetter holds an event that could not be handled by a subscriber.
+type DeadLetter struct {
+	ID       uint64
+	Topic    string
+	Message  any
+	Err      error
+	Attempts int
+	FailedAt time.Time
+
+	sub Subscription
+	evt event
+}
+
+type deadLetters struct {
+	mu      sync.Mutex
+	nextID  uint64
+	letters []DeadLetter
+}
+
+func (d *deadLetters) add(dl DeadLetter) DeadLetter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nextID++
+	dl.ID = d.nextID
+	d.letters = append(d.letters, dl)
+	if len(d.letters) > maxDeadLetters {
+		d.letters = d.letters[len(d.letters)-maxDeadLetters:]
+	}
+	return dl
+}
+
+func (d *deadLetters) list() []DeadLetter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.letters)
+}
+
+func (d *deadLetters) take(id uint64) (DeadLetter, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, dl := range d.letters {
+		if dl.ID == id {
+			d.letters = slices.Delete(d.letters, i, i+1)
+			return dl, true
+		}
+	}
+	return DeadLetter{}, false
+}
+
+// DeadLetters returns the events of the default Bus that could not be
+// handled, oldest first.
+func DeadLetters() []DeadLetter {
+	return Default().DeadLetters()
+}
+
+// Replay replays a dead letter of the default Bus, see Bus.Replay.
+func Replay(id uint64) error {
+	return Default().Replay(id)
+}
+
+// DeadLetters returns the events that could not be handled, oldest first.
+// Only the last 1000 dead letters are kept.
+func (b *Bus) DeadLetters() []DeadLetter {
+	return b.deadLetters.list()
+}
+
+// Replay removes the dead letter with the given ID and delivers its event
+// again to the subscriber that failed to handle it, with a fresh retry budget.
+func (b *Bus) Replay(id uint64) error {
+	dl, ok := b.deadLetters.take(id)
+	if !ok {
+		return fmt.Errorf("dead letter %d not found", id)
+	}
+	if !b.isSubscribed(dl.sub) {
+		return fmt.Errorf("dead letter %d: subscription to %s no longer exists", id, dl.Topic)
+	}
+	go b.handle(dl.sub, dl.evt)
+	return nil
+}
@@ -2,127 +2,146 @@ package event
 
 import (
 	"context"
-	"slices"
-	"sync"
+	"log/slog"
+	"sync/atomic"
 	"time"
 )
 
 // HandlerFunc is the function being called when receiving an event.
-type HandlerFunc func(context.Context, any)
+// A returned error, or a panic, marks the event as failed for this
+// handler, which is then retried based on its RetryPolicy.
+type HandlerFunc func(context.Context, any) error
+
+// SubscribeOption configures a single subscription.
+type SubscribeOption func(*Subscription)
 
 // Emit and event to the given topic
 func Emit(topic string, event any) {
-	stream.emit(topic, event)
+	if err := Default().Emit(context.Background(), topic, event); err != nil {
+		slog.Warn("event: dropped event", "topic", topic, "err", err)
+	}
+}
+
+// TryEmit emits an event to the given topic without blocking and returns
+// false if it was dropped, see Bus.TryEmit.
+func TryEmit(topic string, event any) bool {
+	return Default().TryEmit(context.Background(), topic, event)
+}
+
+// EmitContext emits an event to the given topic. Handlers receive a
+// context carrying the current trace span and the values registered with
+// PropagateValues, but detached from its cancellation and deadline, since
+// they run after the caller might have returned.
+//
+// While the queue is full EmitContext waits until ctx is done, after which
+// the event is dropped and ctx.Err() is returned, unless the topic has a
+// different overflow policy, see SetOverflow.
+func EmitContext(ctx context.Context, topic string, event any) error {
+	return Default().Emit(ctx, topic, event)
+}
+
+// EmitSync calls the handlers subscribed to the topic inline, one after
+// another, and returns their combined errors. Handlers receive ctx as is,
+// including its cancellation and deadline, and are retried according to
+// their retry policy while ctx is not done. Failed events are not dead
+// lettered, the caller is expected to handle the error.
+//
+//	if err := event.EmitSync(ctx, "order.placed", order); err != nil {
+//		return err
+//	}
+func EmitSync(ctx context.Context, topic string, event any) error {
+	return Default().EmitSync(ctx, topic, event)
 }
 
-// Subscribe a HandlerFunc to the given topic.
+// Subscribe a HandlerFunc to the given topic. The topic can be a pattern
+// with wildcards, auth.* matches a single segment and auth.> matches all
+// topics below auth. Use TopicFromContext to get the concrete topic of
+// the event in the handler.
 // A Subscription is being returned that can be used
 // to unsubscribe from the topic.
-func Subscribe(topic string, h HandlerFunc) Subscription {
-	return stream.subscribe(topic, h)
+func Subscribe(topic string, h HandlerFunc, opts ...SubscribeOption) Subscription {
+	return Default().Subscribe(topic, h, opts...)
 }
 
 // Unsubscribe unsubribes the given Subscription from its topic.
 func Unsubscribe(sub Subscription) {
-	stream.unsubscribe(sub)
+	Default().Unsubscribe(sub)
 }
 
-// Stop stops the event stream, cleaning up its resources.
-func Stop() {
-	stream.stop()
+// Topics returns all the topics, or topic patterns, that have subscribers
+// together with their number of subscribers.
+func Topics() map[string]int {
+	return Default().Topics()
 }
 
-var stream *eventStream
-
-type event struct {
-	topic   string
-	message any
-}
-
-// Subscription represents a handler subscribed to a specific topic.
-type Subscription struct {
-	Topic     string
-	CreatedAt int64
-	Fn        HandlerFunc
+// Subscribers returns the number of subscriptions of the default Bus an
+// event emitted to the given topic would be delivered to.
+func Subscribers(topic string) int {
+	return Default().Subscribers(topic)
 }
 
-type eventStream struct {
-	mu      sync.RWMutex
-	subs    map[string][]Subscription
-	eventch chan event
-	quitch  chan struct{}
+// QueueDepth returns the number of emitted events waiting to be
+// dispatched to their subscribers.
+func QueueDepth() int {
+	return Default().QueueDepth()
 }
 
-func newStream() *eventStream {
-	e := &eventStream{
-		subs:    make(map[string][]Subscription),
-		eventch: make(chan event, 128),
-		quitch:  make(chan struct{}),
-	}
-	go e.start()
-	return e
-}
-
-func (e *eventStream) start() {
-	ctx := context.Background()
-	for {
-		select {
-		case <-e.quitch:
-			return
-		case evt := <-e.eventch:
-			if handlers, ok := e.subs[evt.topic]; ok {
-				for _, sub := range handlers {
-					go sub.Fn(ctx, evt.message)
-				}
-			}
-		}
-	}
+// OnHandled registers a function that is called each time a subscriber
+// of any Bus finished handling an event, with the topic and the handling
+// duration.
+func OnHandled(fn func(topic string, d time.Duration)) {
+	AddHooks(Hooks{OnHandled: func(topic string, d time.Duration, _ error) {
+		fn(topic, d)
+	}})
 }
 
-func (e *eventStream) stop() {
-	e.quitch <- struct{}{}
+// Stop stops the default Bus, handling all queued events before returning.
+func Stop() {
+	Default().Stop()
 }
 
-func (e *eventStream) emit(topic string, v any) {
-	e.eventch <- event{
-		topic:   topic,
-		message: v,
-	}
+// TopicFromContext returns the topic the event being handled was emitted
+// to, which is useful for handlers subscribed to a wildcard pattern.
+func TopicFromContext(ctx context.Context) string {
+	topic, _ := ctx.Value(topicKey{}).(string)
+	return topic
 }
 
-func (e *eventStream) subscribe(topic string, h HandlerFunc) Subscription {
-	e.mu.RLock()
-	defer e.mu.RUnlock()
+type topicKey struct{}
 
-	sub := Subscription{
-		CreatedAt: time.Now().UnixNano(),
-		Topic:     topic,
-		Fn:        h,
-	}
+var defaultBus atomic.Pointer[Bus]
 
-	if _, ok := e.subs[topic]; !ok {
-		e.subs[topic] = []Subscription{}
-	}
+// Default returns the Bus used by the package level functions.
+func Default() *Bus {
+	return defaultBus.Load()
+}
 
-	e.subs[topic] = append(e.subs[topic], sub)
+// SetDefault replaces the Bus used by the package level functions, for
+// example with one created with different Options. Subscriptions are not
+// carried over, hence SetDefault is best called before subscribing. The
+// previous Bus is not stopped.
+func SetDefault(b *Bus) {
+	defaultBus.Store(b)
+}
 
-	return sub
+type event struct {
+	ctx     context.Context
+	topic   string
+	message any
 }
 
-func (e *eventStream) unsubscribe(sub Subscription) {
-	e.mu.RLock()
-	defer e.mu.RUnlock()
+// Subscription represents a handler subscribed to a specific topic.
+type Subscription struct {
+	Topic     string
+	CreatedAt int64
+	Fn        HandlerFunc
 
-	if _, ok := e.subs[sub.Topic]; ok {
-		e.subs[sub.Topic] = slices.DeleteFunc(e.subs[sub.Topic], func(e Subscription) bool {
-			return sub.CreatedAt == e.CreatedAt
-		})
-	}
-	if len(e.subs[sub.Topic]) == 0 {
-		delete(e.subs, sub.Topic)
-	}
+	retry          *RetryPolicy
+	middleware     []Middleware
+	skipMiddleware bool
+	replay         int
 }
 
 func init() {
-	stream = newStream()
+	SetDefault(NewBus(Options{}))
 }
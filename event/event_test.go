@@ -2,14 +2,18 @@ package event
 
 import (
 	"context"
+	"errors"
 	"reflect"
+	"slices"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestEventSubscribeEmit(t *testing.T) {
 	expect := 1
 	ctx, cancel := context.WithCancel(context.Background())
-	Subscribe("foo.a", func(_ context.Context, event any) {
+	Subscribe("foo.a", func(_ context.Context, event any) error {
 		defer cancel()
 		value, ok := event.(int)
 		if !ok {
@@ -18,15 +22,259 @@ func TestEventSubscribeEmit(t *testing.T) {
 		if value != 1 {
 			t.Errorf("expected %d got %d", expect, value)
 		}
+		return nil
 	})
 	Emit("foo.a", expect)
 	<-ctx.Done()
 }
 
 func TestUnsubscribe(t *testing.T) {
-	sub := Subscribe("foo.b", func(_ context.Context, _ any) {})
+	sub := Subscribe("foo.b", func(_ context.Context, _ any) error { return nil })
 	Unsubscribe(sub)
-	if _, ok := stream.subs["foo.b"]; ok {
+	if _, ok := Topics()["foo.b"]; ok {
 		t.Errorf("expected topic foo.bar to be deleted")
 	}
 }
+
+func TestWildcardSubscribe(t *testing.T) {
+	topics := make(chan string, 3)
+	sub := Subscribe("bar.*.created", func(ctx context.Context, _ any) error {
+		topics <- "*:" + TopicFromContext(ctx)
+		return nil
+	})
+	defer Unsubscribe(sub)
+	tail := Subscribe("bar.>", func(ctx context.Context, _ any) error {
+		topics <- ">:" + TopicFromContext(ctx)
+		return nil
+	})
+	defer Unsubscribe(tail)
+
+	Emit("bar.user.created", 1)
+	Emit("bar", 1)
+	got := map[string]bool{<-topics: true, <-topics: true}
+	for _, expect := range []string{"*:bar.user.created", ">:bar.user.created"} {
+		if !got[expect] {
+			t.Errorf("expected %s to be handled got %v", expect, got)
+		}
+	}
+	select {
+	case topic := <-topics:
+		t.Errorf("unexpected event %s", topic)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestTrieMatch(t *testing.T) {
+	root := newNode()
+	patterns := []string{"a.b", "a.*", "a.>", "*.b", ">", "a.b.c", "a.*.c", "x"}
+	for i, pattern := range patterns {
+		root.insert(Subscription{Topic: pattern, CreatedAt: int64(i)})
+	}
+	tests := map[string][]string{
+		"a.b":   {"a.b", "a.*", "a.>", "*.b", ">"},
+		"a.b.c": {"a.>", ">", "a.b.c", "a.*.c"},
+		"a":     {">"},
+		"b.b":   {"*.b", ">"},
+		"a.*":   {"a.*", "a.>", ">"},
+		"y":     {">"},
+	}
+	for topic, expect := range tests {
+		var got []string
+		for _, sub := range root.match(splitTopic(topic), nil) {
+			got = append(got, sub.Topic)
+		}
+		slices.Sort(got)
+		slices.Sort(expect)
+		if !slices.Equal(got, expect) {
+			t.Errorf("%s: expected %v got %v", topic, expect, got)
+		}
+	}
+
+	for i, pattern := range patterns {
+		root.remove(Subscription{Topic: pattern, CreatedAt: int64(i)}, splitTopic(pattern))
+	}
+	if len(root.children) != 0 {
+		t.Errorf("expected empty trie got %d children", len(root.children))
+	}
+}
+
+func TestEmitContext(t *testing.T) {
+	type (
+		key       struct{}
+		unrelated struct{}
+	)
+	PropagateValues(key{})
+	ctx := context.WithValue(context.Background(), key{}, "bar")
+	ctx = context.WithValue(ctx, unrelated{}, "baz")
+	ctx, cancel := context.WithTimeout(ctx, time.Hour)
+	cancel()
+
+	done := make(chan struct{})
+	Subscribe("foo.c", func(ctx context.Context, _ any) error {
+		defer close(done)
+		if value := ctx.Value(key{}); value != "bar" {
+			t.Errorf("expected context value bar got %v", value)
+		}
+		if value := ctx.Value(unrelated{}); value != nil {
+			t.Errorf("expected unregistered context value to be dropped got %v", value)
+		}
+		if ctx.Err() != nil {
+			t.Errorf("expected context to be detached from cancellation got %v", ctx.Err())
+		}
+		if _, ok := ctx.Deadline(); ok {
+			t.Errorf("expected context to be detached from its deadline")
+		}
+		return nil
+	})
+	EmitContext(ctx, "foo.c", 1)
+	<-done
+}
+
+func TestRetryAndDeadLetter(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	var attempts atomic.Int32
+	sub := Subscribe("foo.d", func(_ context.Context, _ any) error {
+		if attempts.Add(1) == 2 {
+			panic("boom")
+		}
+		return errors.New("failed")
+	}, WithRetry(policy))
+	defer Unsubscribe(sub)
+
+	deadch := make(chan DeadLetter, 1)
+	dlsub := Subscribe(DeadLetterTopic, func(_ context.Context, event any) error {
+		deadch <- event.(DeadLetter)
+		return nil
+	})
+	defer Unsubscribe(dlsub)
+
+	Emit("foo.d", 1)
+	dl := <-deadch
+	if attempts.Load() != 3 {
+		t.Errorf("expected 3 attempts got %d", attempts.Load())
+	}
+	if dl.Topic != "foo.d" || dl.Message != 1 || dl.Attempts != 3 {
+		t.Errorf("unexpected dead letter %+v", dl)
+	}
+	if dl.Err.Error() != "failed" {
+		t.Errorf("expected error failed got %v", dl.Err)
+	}
+
+	found := false
+	for _, letter := range DeadLetters() {
+		found = found || letter.ID == dl.ID
+	}
+	if !found {
+		t.Errorf("expected dead letter %d to be listed", dl.ID)
+	}
+
+	// Replaying gives the handler a fresh retry budget.
+	if err := Replay(dl.ID); err != nil {
+		t.Fatal(err)
+	}
+	<-deadch
+	if attempts.Load() != 6 {
+		t.Errorf("expected 6 attempts got %d", attempts.Load())
+	}
+	if err := Replay(dl.ID); err == nil {
+		t.Errorf("expected replayed dead letter to be removed")
+	}
+}
+
+func TestPanicError(t *testing.T) {
+	err := call(func(context.Context, any) error { panic("boom") }, context.Background(), nil)
+	var perr *PanicError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *PanicError got %v", err)
+	}
+	if perr.Value != "boom" || len(perr.Stack) == 0 {
+		t.Errorf("unexpected panic error %+v", perr)
+	}
+}
+
+func TestTopic(t *testing.T) {
+	type user struct{ Name string }
+	topic := NewTopic[user]("foo.typed")
+
+	handled := make(chan user, 1)
+	sub := topic.Subscribe(func(_ context.Context, u user) error {
+		handled <- u
+		return nil
+	}, WithRetry(NoRetry))
+	defer Unsubscribe(sub)
+
+	deadch := make(chan DeadLetter, 1)
+	dlsub := Subscribe(DeadLetterTopic, func(_ context.Context, event any) error {
+		if dl := event.(DeadLetter); dl.Topic == topic.Name() {
+			deadch <- dl
+		}
+		return nil
+	})
+	defer Unsubscribe(dlsub)
+
+	topic.Emit(context.Background(), user{Name: "bob"})
+	if u := <-handled; u.Name != "bob" {
+		t.Errorf("expected bob got %s", u.Name)
+	}
+
+	Emit(topic.Name(), "bob")
+	dl := <-deadch
+	if dl.Message != "bob" || dl.Err == nil {
+		t.Errorf("expected mismatched event to be dead lettered got %+v", dl)
+	}
+}
+
+func TestEmitSync(t *testing.T) {
+	type key struct{}
+	var calls []string
+	sub := Subscribe("foo.sync", func(ctx context.Context, _ any) error {
+		calls = append(calls, ctx.Value(key{}).(string))
+		return nil
+	})
+	defer Unsubscribe(sub)
+	failing := Subscribe("foo.*", func(context.Context, any) error {
+		return errors.New("failed")
+	}, WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+	defer Unsubscribe(failing)
+
+	ctx := context.WithValue(context.Background(), key{}, "bar")
+	err := EmitSync(ctx, "foo.sync", 1)
+	if len(calls) != 1 || calls[0] != "bar" {
+		t.Errorf("expected handler to be called inline with the context got %v", calls)
+	}
+	if err == nil || err.Error() != "foo.*: failed" {
+		t.Errorf("expected error of the failing handler got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := EmitSync(ctx, "foo.sync", 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled got %v", err)
+	}
+}
+
+func TestDeadLetterSkipsCatchAll(t *testing.T) {
+	bus := NewBus(Options{Synchronous: true, Retry: &NoRetry})
+	defer bus.Stop()
+
+	var all, dead []string
+	bus.Subscribe(">", func(ctx context.Context, _ any) error {
+		all = append(all, TopicFromContext(ctx))
+		return nil
+	})
+	bus.Subscribe("event.>", func(ctx context.Context, v any) error {
+		dead = append(dead, v.(DeadLetter).Topic)
+		return nil
+	})
+	bus.Subscribe("job", func(context.Context, any) error {
+		return errors.New("failed")
+	})
+	bus.Emit(context.Background(), "job", 1)
+
+	if len(all) != 1 || all[0] != "job" {
+		t.Errorf("expected the catch-all subscriber to only get the job got %v", all)
+	}
+	if len(dead) != 1 || dead[0] != "job" {
+		t.Errorf("expected the dead letter of the job got %v", dead)
+	}
+}
@@ -0,0 +1,176 @@
+// Package eventtest records the events emitted during a test.
+//
+//	func TestSignup(t *testing.T) {
+//		rec := eventtest.NewRecorder(t)
+//		...
+//		rec.AssertEmitted(t, "auth.signup", func(e auth.UserWithVerificationToken) bool {
+//			return e.User.Email == "bob@example.com"
+//		})
+//	}
+package eventtest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/anthdm/superkit/event"
+)
+
+// Event is an event recorded by a Recorder.
+type Event struct {
+	Topic   string
+	Payload any
+}
+
+// Recorder records all events emitted on the default Bus for the duration
+// of a test.
+type Recorder struct {
+	bus *event.Bus
+
+	mu     sync.RWMutex
+	events []Event
+}
+
+// NewRecorder replaces the default Bus with a synchronous one, so events
+// are handled before Emit returns, and records all events emitted on it,
+// including dead letters.
+// The previous Bus is restored when the test finishes.
+//
+// Subscriptions of the previous Bus are not carried over, subscribe the
+// handlers under test after calling NewRecorder. Failed handlers are not
+// retried unless they are subscribed with their own retry policy.
+func NewRecorder(t testing.TB) *Recorder {
+	t.Helper()
+	r := &Recorder{
+		bus: event.NewBus(event.Options{
+			Synchronous: true,
+			Retry:       &event.NoRetry,
+		}),
+	}
+	record := func(ctx context.Context, v any) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.events = append(r.events, Event{Topic: event.TopicFromContext(ctx), Payload: v})
+		return nil
+	}
+	// Dead letters skip the catch-all subscription, they are recorded
+	// with their own.
+	r.bus.Subscribe(">", func(ctx context.Context, v any) error {
+		if event.TopicFromContext(ctx) == event.DeadLetterTopic {
+			return nil
+		}
+		return record(ctx, v)
+	}, event.WithoutMiddleware())
+	r.bus.Subscribe(event.DeadLetterTopic, record, event.WithoutMiddleware())
+
+	prev := event.Default()
+	event.SetDefault(r.bus)
+	t.Cleanup(func() {
+		event.SetDefault(prev)
+		r.bus.Stop()
+	})
+	return r
+}
+
+// Bus returns the synchronous Bus the events are recorded on.
+func (r *Recorder) Bus() *event.Bus {
+	return r.bus
+}
+
+// Events returns all the recorded events in the order they were emitted.
+func (r *Recorder) Events() []Event {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	events := make([]Event, len(r.events))
+	copy(events, r.events)
+	return events
+}
+
+// Emitted returns the payloads of the events emitted to the given topic.
+func (r *Recorder) Emitted(topic string) []any {
+	var payloads []any
+	for _, e := range r.Events() {
+		if e.Topic == topic {
+			payloads = append(payloads, e.Payload)
+		}
+	}
+	return payloads
+}
+
+// Reset removes all the recorded events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+// AssertEmitted asserts that an event matching the matcher was emitted to
+// the given topic. The matcher is either nil, which matches any event, a
+// func(T) bool, which is only called for payloads of type T, or a value
+// that is compared with reflect.DeepEqual.
+func (r *Recorder) AssertEmitted(t testing.TB, topic string, matcher any) bool {
+	t.Helper()
+	payloads := r.Emitted(topic)
+	for _, payload := range payloads {
+		if match(matcher, payload) {
+			return true
+		}
+	}
+	if len(payloads) == 0 {
+		t.Errorf("expected an event to be emitted to %s, got none", topic)
+	} else {
+		t.Errorf("expected a matching event to be emitted to %s, got %d events that did not match: %s",
+			topic, len(payloads), format(payloads))
+	}
+	return false
+}
+
+// AssertNotEmitted asserts that no event matching the matcher was emitted
+// to the given topic, see AssertEmitted for the matcher.
+func (r *Recorder) AssertNotEmitted(t testing.TB, topic string, matcher any) bool {
+	t.Helper()
+	var matched []any
+	for _, payload := range r.Emitted(topic) {
+		if match(matcher, payload) {
+			matched = append(matched, payload)
+		}
+	}
+	if len(matched) > 0 {
+		t.Errorf("expected no matching event to be emitted to %s, got %s", topic, format(matched))
+		return false
+	}
+	return true
+}
+
+func match(matcher, payload any) bool {
+	if matcher == nil {
+		return true
+	}
+	fn := reflect.ValueOf(matcher)
+	typ := fn.Type()
+	if typ.Kind() == reflect.Func && typ.NumIn() == 1 && typ.NumOut() == 1 && typ.Out(0).Kind() == reflect.Bool {
+		in := reflect.ValueOf(payload)
+		if !in.IsValid() {
+			in = reflect.Zero(typ.In(0))
+		}
+		if !in.Type().AssignableTo(typ.In(0)) {
+			return false
+		}
+		return fn.Call([]reflect.Value{in})[0].Bool()
+	}
+	return reflect.DeepEqual(matcher, payload)
+}
+
+func format(payloads []any) string {
+	s := ""
+	for i, payload := range payloads {
+		if i > 0 {
+			s += ", "
+		}
+		s += fmt.Sprintf("%+v", payload)
+	}
+	return s
+}
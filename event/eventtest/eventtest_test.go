@@ -0,0 +1,93 @@
+package eventtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anthdm/superkit/event"
+)
+
+type user struct {
+	Email string
+}
+
+var userCreated = event.NewTopic[user]("user.created")
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder(t)
+
+	var handled []string
+	userCreated.Subscribe(func(_ context.Context, u user) error {
+		handled = append(handled, u.Email)
+		return nil
+	})
+	userCreated.Emit(context.Background(), user{Email: "bob@example.com"})
+	event.Emit("user.deleted", 1)
+
+	// Handlers run synchronously, no waiting is needed.
+	if len(handled) != 1 {
+		t.Fatalf("expected the handler to be called before emit returned got %v", handled)
+	}
+	rec.AssertEmitted(t, "user.created", nil)
+	rec.AssertEmitted(t, "user.created", user{Email: "bob@example.com"})
+	rec.AssertEmitted(t, "user.created", func(u user) bool { return u.Email == "bob@example.com" })
+	rec.AssertEmitted(t, "user.deleted", 1)
+	rec.AssertNotEmitted(t, "user.created", func(u user) bool { return u.Email == "alice@example.com" })
+	rec.AssertNotEmitted(t, "user.updated", nil)
+
+	if events := rec.Events(); len(events) != 2 || events[1].Topic != "user.deleted" {
+		t.Errorf("unexpected events %+v", events)
+	}
+
+	ft := &fakeT{}
+	if rec.AssertEmitted(ft, "user.created", func(n int) bool { return true }) {
+		t.Errorf("expected a matcher of another type not to match")
+	}
+	if rec.AssertNotEmitted(ft, "user.deleted", nil) {
+		t.Errorf("expected AssertNotEmitted to fail")
+	}
+	if len(ft.errors) != 2 {
+		t.Errorf("expected 2 failures got %v", ft.errors)
+	}
+
+	rec.Reset()
+	rec.AssertNotEmitted(t, "user.created", nil)
+}
+
+func TestRecorderDeadLetter(t *testing.T) {
+	rec := NewRecorder(t)
+	event.Subscribe("job", func(context.Context, any) error {
+		return errors.New("failed")
+	})
+	event.Emit("job", 1)
+	rec.AssertEmitted(t, event.DeadLetterTopic, func(dl event.DeadLetter) bool {
+		return dl.Topic == "job" && dl.Attempts == 1
+	})
+}
+
+func TestRecorderRestoresDefault(t *testing.T) {
+	prev := event.Default()
+	t.Run("recorder", func(t *testing.T) {
+		rec := NewRecorder(t)
+		if event.Default() != rec.Bus() {
+			t.Errorf("expected the recorder bus to be the default")
+		}
+	})
+	if event.Default() != prev {
+		t.Errorf("expected the previous bus to be restored")
+	}
+}
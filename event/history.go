@@ -0,0 +1,208 @@
+package event
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// HistoryEvent is an event kept in the history of a Bus.
+type HistoryEvent struct {
+	Topic     string
+	Payload   any
+	EmittedAt time.Time
+
+	evt event
+}
+
+// KeepHistory enables the history of the default Bus, see Bus.KeepHistory.
+func KeepHistory(size int, maxAge time.Duration) {
+	Default().KeepHistory(size, maxAge)
+}
+
+// History returns the history of the default Bus, see Bus.History.
+func History(pattern string) []HistoryEvent {
+	return Default().History(pattern)
+}
+
+// WithReplay delivers up to the last n events of the history matching the
+// topic of the subscription to the new subscriber, oldest first, for
+// example to catch up a reconnecting SSE client. The history has to be
+// enabled with KeepHistory.
+func WithReplay(n int) SubscribeOption {
+	return func(sub *Subscription) {
+		sub.replay = n
+	}
+}
+
+type history struct {
+	mu     sync.RWMutex
+	size   int
+	maxAge time.Duration
+	topics map[string][]HistoryEvent
+	// nextSweep is when record next drops the expired events of all
+	// topics, not only of the one it records.
+	nextSweep time.Time
+}
+
+func (h *history) enabled() bool {
+	return h.size > 0 || h.maxAge > 0
+}
+
+// prune drops the events of the topic older than maxAge, if it is
+// positive.
+func (h *history) prune(topic string, now time.Time) {
+	if h.maxAge <= 0 {
+		return
+	}
+	events := h.topics[topic]
+	cutoff := now.Add(-h.maxAge)
+	i := 0
+	for i < len(events) && !events[i].EmittedAt.After(cutoff) {
+		i++
+	}
+	switch {
+	case i == len(events):
+		delete(h.topics, topic)
+	case i > 0:
+		h.topics[topic] = slices.Delete(events, 0, i)
+	}
+}
+
+// KeepHistory keeps the last size events of every topic and drops the
+// events older than maxAge. Either one may be zero, to keep events only
+// by count or only by age. Both zero disables the history, which is the
+// default.
+func (b *Bus) KeepHistory(size int, maxAge time.Duration) {
+	h := &b.history
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.size = max(size, 0)
+	h.maxAge = max(maxAge, 0)
+	if !h.enabled() {
+		h.topics = nil
+		return
+	}
+	now := time.Now()
+	for topic, events := range h.topics {
+		if h.size > 0 && len(events) > h.size {
+			h.topics[topic] = slices.Clone(events[len(events)-h.size:])
+		}
+		h.prune(topic, now)
+	}
+}
+
+// HistoryEnabled returns true if the Bus keeps a history.
+func (b *Bus) HistoryEnabled() bool {
+	b.history.mu.RLock()
+	defer b.history.mu.RUnlock()
+	return b.history.enabled()
+}
+
+// History returns the events of the history whose topic matches the
+// pattern, oldest first. Use > for all topics.
+func (b *Bus) History(pattern string) []HistoryEvent {
+	h := &b.history
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	var cutoff time.Time
+	if h.maxAge > 0 {
+		cutoff = time.Now().Add(-h.maxAge)
+	}
+	var events []HistoryEvent
+	for topic, topicEvents := range h.topics {
+		if !matchTopic(pattern, topic) {
+			continue
+		}
+		for _, e := range topicEvents {
+			if e.EmittedAt.After(cutoff) {
+				events = append(events, e)
+			}
+		}
+	}
+	slices.SortStableFunc(events, func(a, b HistoryEvent) int {
+		return a.EmittedAt.Compare(b.EmittedAt)
+	})
+	return events
+}
+
+func (b *Bus) record(evt event) {
+	h := &b.history
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.enabled() {
+		return
+	}
+	if h.topics == nil {
+		h.topics = make(map[string][]HistoryEvent)
+	}
+	now := time.Now()
+	events := append(h.topics[evt.topic], HistoryEvent{
+		Topic:     evt.topic,
+		Payload:   evt.message,
+		EmittedAt: now,
+		evt:       evt,
+	})
+	if h.size > 0 && len(events) > h.size {
+		events = slices.Delete(events, 0, len(events)-h.size)
+	}
+	h.topics[evt.topic] = events
+	h.prune(evt.topic, now)
+	// Topics that are no longer emitted on are swept once per maxAge, so
+	// their expired payloads don't linger.
+	if h.maxAge > 0 && now.After(h.nextSweep) {
+		for topic := range h.topics {
+			h.prune(topic, now)
+		}
+		h.nextSweep = now.Add(h.maxAge)
+	}
+}
+
+// replay delivers the last events of the history matching the topic of
+// the subscription to it.
+func (b *Bus) replay(sub Subscription) {
+	events := b.History(sub.Topic)
+	if len(events) > sub.replay {
+		events = events[len(events)-sub.replay:]
+	}
+	if len(events) == 0 {
+		return
+	}
+	deliver := func() {
+		for _, e := range events {
+			evt := e.evt
+			evt.ctx = context.WithValue(evt.ctx, replayKey{}, true)
+			b.handle(sub, evt)
+		}
+	}
+	if b.synchronous {
+		deliver()
+		return
+	}
+	go deliver()
+}
+
+type replayKey struct{}
+
+// IsReplay returns true if the event being handled is replayed from the
+// history, see WithReplay.
+func IsReplay(ctx context.Context) bool {
+	replay, _ := ctx.Value(replayKey{}).(bool)
+	return replay
+}
+
+// matchTopic returns true if the topic matches the pattern, see Subscribe
+// for the wildcards.
+func matchTopic(pattern, topic string) bool {
+	psegs, tsegs := splitTopic(pattern), splitTopic(topic)
+	for i, seg := range psegs {
+		if seg == wildcardTail && i == len(psegs)-1 {
+			return len(tsegs) > i
+		}
+		if i >= len(tsegs) || (seg != wildcardOne && seg != tsegs[i]) {
+			return false
+		}
+	}
+	return len(psegs) == len(tsegs)
+}
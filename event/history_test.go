@@ -0,0 +1,129 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	bus := NewBus(Options{Synchronous: true, HistorySize: 2})
+	defer bus.Stop()
+
+	for i := range 3 {
+		bus.Emit(context.Background(), "chat.room1", i)
+	}
+	bus.Emit(context.Background(), "chat.room2", 10)
+	bus.Emit(context.Background(), "user.created", 20)
+
+	var payloads []any
+	for _, e := range bus.History("chat.*") {
+		payloads = append(payloads, e.Payload)
+	}
+	if len(payloads) != 3 || payloads[0] != 1 || payloads[1] != 2 || payloads[2] != 10 {
+		t.Errorf("expected the last 2 events per topic got %v", payloads)
+	}
+	if n := len(bus.History(">")); n != 4 {
+		t.Errorf("expected 4 events in total got %d", n)
+	}
+
+	bus.KeepHistory(2, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if n := len(bus.History(">")); n != 0 {
+		t.Errorf("expected expired events to be dropped got %d", n)
+	}
+
+	bus.KeepHistory(0, 0)
+	bus.Emit(context.Background(), "chat.room1", 4)
+	if bus.HistoryEnabled() || len(bus.History(">")) != 0 {
+		t.Errorf("expected the history to be disabled")
+	}
+}
+
+func TestHistoryRetention(t *testing.T) {
+	bus := NewBus(Options{Synchronous: true})
+	defer bus.Stop()
+
+	// Count only, events never expire.
+	bus.KeepHistory(3, 0)
+	for i := range 5 {
+		bus.Emit(context.Background(), "orders", i)
+	}
+	if n := len(bus.History(">")); n != 3 {
+		t.Errorf("expected the last 3 events got %d", n)
+	}
+
+	// Age only, any number of events is kept until they expire.
+	bus.KeepHistory(0, 50*time.Millisecond)
+	if !bus.HistoryEnabled() {
+		t.Fatal("expected a time based history to be enabled")
+	}
+	for i := range 10 {
+		bus.Emit(context.Background(), "orders", i)
+	}
+	if n := len(bus.History("orders")); n != 13 {
+		t.Errorf("expected 13 events got %d", n)
+	}
+
+	// Recording an event evicts the expired events, of other topics too.
+	time.Sleep(60 * time.Millisecond)
+	bus.Emit(context.Background(), "users", 1)
+	bus.history.mu.RLock()
+	_, kept := bus.history.topics["orders"]
+	bus.history.mu.RUnlock()
+	if kept {
+		t.Error("expected the expired events to be evicted")
+	}
+	if n := len(bus.History(">")); n != 1 {
+		t.Errorf("expected 1 event got %d", n)
+	}
+}
+
+func TestWithReplay(t *testing.T) {
+	bus := NewBus(Options{HistorySize: 10})
+	defer bus.Stop()
+	for i := range 5 {
+		bus.Emit(context.Background(), "chat.room1", i)
+	}
+	// Wait until the emitted events are recorded and dispatched.
+	for bus.QueueDepth() > 0 || len(bus.History(">")) < 5 {
+		time.Sleep(time.Millisecond)
+	}
+
+	handled := make(chan any, 10)
+	bus.Subscribe("chat.>", func(ctx context.Context, v any) error {
+		if !IsReplay(ctx) {
+			t.Errorf("expected event %v to be a replay", v)
+		}
+		handled <- v
+		return nil
+	}, WithReplay(3))
+	for _, expect := range []int{2, 3, 4} {
+		if v := <-handled; v != expect {
+			t.Errorf("expected %d got %v", expect, v)
+		}
+	}
+}
+
+func TestMatchTopic(t *testing.T) {
+	tests := []struct {
+		pattern string
+		topic   string
+		match   bool
+	}{
+		{"a.b", "a.b", true},
+		{"a.b", "a.c", false},
+		{"a.*", "a.b", true},
+		{"a.*", "a.b.c", false},
+		{"a.>", "a.b.c", true},
+		{"a.>", "a", false},
+		{">", "a", true},
+		{"*.b", "a.b", true},
+		{"a", "a.b", false},
+	}
+	for _, test := range tests {
+		if got := matchTopic(test.pattern, test.topic); got != test.match {
+			t.Errorf("%s %s: expected %v got %v", test.pattern, test.topic, test.match, got)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package event
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Middleware wraps a HandlerFunc with cross-cutting behavior, like HTTP
+// middleware does for http.Handler.
+//
+//	event.Use(func(next event.HandlerFunc) event.HandlerFunc {
+//		return func(ctx context.Context, v any) error {
+//			start := time.Now()
+//			err := next(ctx, v)
+//			slog.Info("event handled", "topic", event.TopicFromContext(ctx), "took", time.Since(start))
+//			return err
+//		}
+//	})
+//
+// Tracing and recovering from panics are built into the Bus and surround
+// all middleware.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+var (
+	globalmu         sync.RWMutex
+	globalMiddleware []Middleware
+	globalHooks      []Hooks
+)
+
+// Use adds middleware that wraps the handlers of every Bus. It runs
+// before the middleware added with Bus.Use.
+func Use(mw ...Middleware) {
+	globalmu.Lock()
+	defer globalmu.Unlock()
+	globalMiddleware = append(globalMiddleware, mw...)
+}
+
+// WithMiddleware adds middleware to a single subscription. It runs after
+// the middleware of the Bus.
+func WithMiddleware(mw ...Middleware) SubscribeOption {
+	return func(sub *Subscription) {
+		sub.middleware = append(sub.middleware, mw...)
+	}
+}
+
+// WithoutMiddleware skips the middleware added with Use and Bus.Use for a
+// subscription, middleware added with WithMiddleware still applies.
+func WithoutMiddleware() SubscribeOption {
+	return func(sub *Subscription) {
+		sub.skipMiddleware = true
+	}
+}
+
+// Logger returns middleware logging every handled event with its topic,
+// duration and error.
+func Logger(logger *slog.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, v any) error {
+			start := time.Now()
+			err := next(ctx, v)
+			attrs := []any{"topic", TopicFromContext(ctx), "took", time.Since(start)}
+			if err != nil {
+				logger.ErrorContext(ctx, "event handler failed", append(attrs, "err", err)...)
+			} else {
+				logger.InfoContext(ctx, "event handled", attrs...)
+			}
+			return err
+		}
+	}
+}
+
+// Timeout returns middleware that cancels the context of a handler
+// after the given duration.
+func Timeout(d time.Duration) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, v any) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return next(ctx, v)
+		}
+	}
+}
+
+// chain wraps h with the middleware, the first one being the outermost.
+func chain(h HandlerFunc, mw ...[]Middleware) HandlerFunc {
+	for i := len(mw) - 1; i >= 0; i-- {
+		for j := len(mw[i]) - 1; j >= 0; j-- {
+			h = mw[i][j](h)
+		}
+	}
+	return h
+}
@@ -0,0 +1,101 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	bus := NewBus(Options{Retry: &NoRetry})
+
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	record := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, v any) error {
+				mu.Lock()
+				calls = append(calls, name)
+				mu.Unlock()
+				return next(ctx, v)
+			}
+		}
+	}
+	bus.Use(record("bus1"), record("bus2"))
+	handler := func(name string) HandlerFunc {
+		return func(context.Context, any) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	bus.Subscribe("a", handler("a"), WithMiddleware(record("sub")))
+	bus.Subscribe("b", handler("b"), WithoutMiddleware(), WithMiddleware(record("sub")))
+	bus.Emit(context.Background(), "a", 1)
+	bus.Stop()
+	if expect := []string{"bus1", "bus2", "sub", "a"}; !slices.Equal(calls, expect) {
+		t.Errorf("expected %v got %v", expect, calls)
+	}
+
+	calls = nil
+	if err := bus.EmitSync(context.Background(), "b", 1); err != nil {
+		t.Fatal(err)
+	}
+	if expect := []string{"sub", "b"}; !slices.Equal(calls, expect) {
+		t.Errorf("expected %v got %v", expect, calls)
+	}
+}
+
+func TestStatsAndHooks(t *testing.T) {
+	bus := NewBus(Options{Retry: &RetryPolicy{MaxAttempts: 2}})
+
+	var (
+		mu          sync.Mutex
+		emitted     []string
+		failed      int
+		deadLetters []DeadLetter
+	)
+	bus.AddHooks(Hooks{
+		OnEmit: func(topic string) {
+			mu.Lock()
+			defer mu.Unlock()
+			emitted = append(emitted, topic)
+		},
+		OnHandled: func(_ string, _ time.Duration, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+			}
+		},
+		OnDeadLetter: func(dl DeadLetter) {
+			mu.Lock()
+			defer mu.Unlock()
+			deadLetters = append(deadLetters, dl)
+		},
+	})
+	bus.Subscribe("ok", func(context.Context, any) error { return nil })
+	bus.Subscribe("fail", func(context.Context, any) error { return errors.New("failed") })
+	bus.Emit(context.Background(), "ok", 1)
+	bus.Emit(context.Background(), "ok", 2)
+	bus.Emit(context.Background(), "fail", 3)
+	bus.Stop()
+
+	stats := bus.Stats()
+	if s := stats["ok"]; s.Emitted != 2 || s.Handled != 2 || s.Failed != 0 || s.QueueDepth != 0 {
+		t.Errorf("unexpected stats of ok %+v", s)
+	}
+	if s := stats["fail"]; s.Emitted != 1 || s.Failed != 2 || s.DeadLettered != 1 || s.QueueDepth != 0 {
+		t.Errorf("unexpected stats of fail %+v", s)
+	}
+	if len(emitted) != 3 || failed != 2 || len(deadLetters) != 1 || deadLetters[0].Topic != "fail" {
+		t.Errorf("unexpected hook calls %v %d %v", emitted, failed, deadLetters)
+	}
+}
@@ -0,0 +1,205 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Execer executes a statement. It is implemented by *sql.DB, *sql.Tx and
+// *sql.Conn, as well as by the ConnPool of a gorm transaction:
+//
+//	db.Get().Transaction(func(tx *gorm.DB) error {
+//		...
+//		return outbox.Emit(ctx, tx.Statement.ConnPool, "auth.signup", user)
+//	})
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Outbox stores events in a SQL table in the same transaction as the
+// change they belong to, so they are never lost when the process dies
+// before they are handled. Relay dispatches the stored events to the
+// subscribers after the transaction has been committed.
+//
+// The table is expected to have the following layout:
+//
+//	create table if not exists event_outbox(
+//		id text primary key,
+//		topic text not null,
+//		payload text not null,
+//		created_at datetime not null,
+//		processed_at datetime
+//	);
+//
+// Events are delivered at least once. A handler may see the same event
+// twice, for example when the process dies after handling an event but
+// before marking it as processed, hence handlers that are not idempotent
+// should deduplicate on EventID.
+type Outbox struct {
+	DB *sql.DB
+	// Table defaults to event_outbox.
+	Table string
+	// Bus the events are dispatched to. Defaults to the default Bus.
+	Bus *Bus
+	// PollInterval is the time between looking for new events.
+	// Defaults to 1 second.
+	PollInterval time.Duration
+	// BatchSize is the maximum number of events dispatched per poll.
+	// Defaults to 100.
+	BatchSize int
+	// Retention is how long processed events are kept before they are
+	// deleted. Defaults to 24 hours.
+	Retention time.Duration
+}
+
+// Emit stores the event in the outbox using the given Execer, typically
+// the transaction of the change the event belongs to. The payload is
+// encoded as JSON and decoded into the type registered with
+// RegisterPayload, or by NewTopic, when it is relayed.
+func (o *Outbox) Emit(ctx context.Context, tx Execer, topic string, v any) error {
+	payload, err := JSONCodec.Encode(v)
+	if err != nil {
+		return fmt.Errorf("event: failed to encode outbox payload of topic %s: %w", topic, err)
+	}
+	query := fmt.Sprintf("insert into %s (id, topic, payload, created_at) values (?, ?, ?, ?)", o.table())
+	_, err = tx.ExecContext(ctx, query, newEventID(), topic, string(payload), time.Now().UTC())
+	return err
+}
+
+// Relay dispatches the stored events to their subscribers until ctx is
+// done. An event is marked as processed once all its handlers returned,
+// including the ones that ended up as dead letters. Only run a single
+// Relay per outbox table.
+//
+//	go outbox.Relay(ctx)
+func (o *Outbox) Relay(ctx context.Context) error {
+	interval := o.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := o.Process(ctx); err != nil {
+			slog.Error("event: outbox relay failed", "err", err)
+		}
+		if err := o.Cleanup(ctx); err != nil {
+			slog.Error("event: outbox cleanup failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Process dispatches a single batch of unprocessed events and returns
+// the number of events that were processed.
+func (o *Outbox) Process(ctx context.Context) (int, error) {
+	batchSize := o.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	query := fmt.Sprintf("select id, topic, payload from %s where processed_at is null order by created_at, id limit ?", o.table())
+	update := fmt.Sprintf("update %s set processed_at = ? where id = ?", o.table())
+	stored := storedEvents{
+		db:     o.DB,
+		bus:    o.Bus,
+		source: "outbox",
+		done: func(ctx context.Context, id string) error {
+			_, err := o.DB.ExecContext(ctx, update, time.Now().UTC(), id)
+			return err
+		},
+	}
+	return stored.process(ctx, query, batchSize)
+}
+
+// Cleanup deletes the processed events older than the retention.
+func (o *Outbox) Cleanup(ctx context.Context) error {
+	retention := o.Retention
+	if retention <= 0 {
+		retention = 24 * time.Hour
+	}
+	query := fmt.Sprintf("delete from %s where processed_at is not null and processed_at < ?", o.table())
+	_, err := o.DB.ExecContext(ctx, query, time.Now().UTC().Add(-retention))
+	return err
+}
+
+func (o *Outbox) table() string {
+	if len(o.Table) == 0 {
+		return "event_outbox"
+	}
+	return o.Table
+}
+
+// storedEvents delivers the events stored in a table, by the Outbox or the
+// Scheduler.
+type storedEvents struct {
+	db  *sql.DB
+	bus *Bus
+	// source names the table in logs, like "outbox".
+	source string
+	// emitted counts the events as emitted on the bus, for events that
+	// weren't counted when they were stored.
+	emitted bool
+	// done marks the event as handled, so the next batch skips it.
+	done func(ctx context.Context, id string) error
+}
+
+// process delivers the batch of events returned by the query and returns
+// the number of events done.
+func (s storedEvents) process(ctx context.Context, query string, args ...any) (int, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	type row struct {
+		id      string
+		topic   string
+		payload string
+	}
+	var batch []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.topic, &r.payload); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		batch = append(batch, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	bus := s.bus
+	if bus == nil {
+		bus = Default()
+	}
+	for i, r := range batch {
+		v, err := decodePayload(JSONCodec, r.topic, []byte(r.payload))
+		if err != nil {
+			// A payload that can't be decoded never will be, hence it
+			// is dead lettered instead of blocking the table.
+			bus.deadLettered(bus.deadLetters.add(DeadLetter{Topic: r.topic, Message: r.payload, Err: err, FailedAt: time.Now()}))
+			slog.Error("event: failed to decode "+s.source+" payload", "topic", r.topic, "id", r.id, "err", err)
+		} else {
+			if s.emitted {
+				bus.emitted(r.topic)
+			}
+			bus.deliver(event{
+				ctx:     context.WithValue(context.WithoutCancel(ctx), eventIDKey{}, r.id),
+				topic:   r.topic,
+				message: v,
+			})
+		}
+		if err := s.done(ctx, r.id); err != nil {
+			return i, err
+		}
+	}
+	return len(batch), nil
+}
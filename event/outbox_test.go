@@ -0,0 +1,118 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newTestOutbox(t *testing.T) *Outbox {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	// Every connection to :memory: has its own database.
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`create table event_outbox(
+		id text primary key,
+		topic text not null,
+		payload text not null,
+		created_at datetime not null,
+		processed_at datetime
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := NewBus(Options{})
+	t.Cleanup(bus.Stop)
+	return &Outbox{DB: db, Bus: bus}
+}
+
+func TestOutbox(t *testing.T) {
+	type user struct{ Name string }
+	topic := NewTopic[user]("outbox.signup")
+	outbox := newTestOutbox(t)
+	ctx := context.Background()
+
+	var handled []user
+	var ids []string
+	outbox.Bus.Subscribe(topic.Name(), func(ctx context.Context, event any) error {
+		handled = append(handled, event.(user))
+		ids = append(ids, EventID(ctx))
+		return nil
+	})
+
+	// A rolled back transaction never emits its events.
+	tx, err := outbox.DB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := topic.EmitOutbox(ctx, outbox, tx, user{Name: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	tx.Rollback()
+
+	tx, err = outbox.DB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := topic.EmitOutbox(ctx, outbox, tx, user{Name: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := outbox.Process(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || len(handled) != 1 || handled[0].Name != "bob" {
+		t.Fatalf("expected bob to be handled once got %d %v", n, handled)
+	}
+	if len(ids[0]) != 32 {
+		t.Errorf("expected event ID got %q", ids[0])
+	}
+
+	// Processed events are not dispatched again.
+	if n, _ := outbox.Process(ctx); n != 0 {
+		t.Errorf("expected no events got %d", n)
+	}
+
+	outbox.Retention = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if err := outbox.Cleanup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	outbox.DB.QueryRow("select count(*) from event_outbox").Scan(&count)
+	if count != 0 {
+		t.Errorf("expected processed events to be deleted got %d", count)
+	}
+}
+
+func TestOutboxUnregisteredPayload(t *testing.T) {
+	outbox := newTestOutbox(t)
+	ctx := context.Background()
+
+	var got any
+	outbox.Bus.Subscribe("outbox.raw", func(_ context.Context, event any) error {
+		got = event
+		return nil
+	})
+	if err := outbox.Emit(ctx, outbox.DB, "outbox.raw", map[string]int{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := outbox.Process(ctx); err != nil {
+		t.Fatal(err)
+	}
+	raw, ok := got.(json.RawMessage)
+	if !ok || string(raw) != `{"a":1}` {
+		t.Errorf("expected raw json payload got %v", got)
+	}
+}
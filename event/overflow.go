@@ -0,0 +1,399 @@
+package event
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/anthdm/superkit/kit/trace"
+)
+
+var (
+	// ErrQueueFull is returned when an event is dropped because the queue
+	// of the Bus is full.
+	ErrQueueFull = errors.New("event: queue full")
+	// ErrStopped is returned when emitting on a stopped Bus.
+	ErrStopped = errors.New("event: bus stopped")
+)
+
+// OverflowPolicy configures what happens to an event emitted while the
+// queue of the Bus is full.
+type OverflowPolicy int
+
+const (
+	// Block waits for room in the queue, or until the context passed to
+	// EmitContext is done.
+	Block OverflowPolicy = iota
+	// DropNewest drops the emitted event.
+	DropNewest
+	// DropOldest keeps the event in a backlog of the topic, dropping the
+	// oldest event of the backlog once it holds OverflowSize events.
+	DropOldest
+	// SpillToDisk writes the event to a file in the SpillDir and queues it
+	// again once there is room. Spilled payloads are encoded with the Codec
+	// of the Bus and decoded like the payloads received over a Transport,
+	// hence only the trace is carried, not the values registered with
+	// PropagateValues. Spilled events are lost when the process dies, use
+	// an Outbox for events that must not be lost.
+	SpillToDisk
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case DropNewest:
+		return "drop newest"
+	case DropOldest:
+		return "drop oldest"
+	case SpillToDisk:
+		return "spill to disk"
+	default:
+		return "block"
+	}
+}
+
+// SetOverflow sets the overflow policy of the topics matching the pattern
+// on the default Bus, see Bus.SetOverflow.
+func SetOverflow(pattern string, policy OverflowPolicy) {
+	Default().SetOverflow(pattern, policy)
+}
+
+// SetOverflow sets the overflow policy of the topics matching the pattern,
+// which may contain wildcards. When several patterns match a topic the
+// one set last wins. Topics without a policy use Options.Overflow.
+//
+//	bus.SetOverflow("analytics.>", event.DropOldest)
+func (b *Bus) SetOverflow(pattern string, policy OverflowPolicy) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.overflowRules = append(b.overflowRules, overflowRule{pattern: pattern, policy: policy})
+}
+
+type overflowRule struct {
+	pattern string
+	policy  OverflowPolicy
+}
+
+func (b *Bus) overflowPolicy(topic string) OverflowPolicy {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for i := len(b.overflowRules) - 1; i >= 0; i-- {
+		if matchTopic(b.overflowRules[i].pattern, topic) {
+			return b.overflowRules[i].policy
+		}
+	}
+	return b.defaultOverflow
+}
+
+// overflow holds the events that did not fit in the queue, until drain
+// moves them to the queue.
+type overflow struct {
+	mu      sync.Mutex
+	size    int
+	dir     string
+	backlog map[string][]event
+	order   []string
+	// pending counts the events per topic in the backlog and spill file,
+	// until drain moved them to the queue, later events of such a topic
+	// are queued behind them.
+	pending map[string]int
+	spill   *spillFile
+	turn    int
+
+	signal chan struct{}
+	quit   chan struct{}
+	done   chan struct{}
+}
+
+type spillFile struct {
+	path  string
+	w     *os.File
+	r     *os.File
+	br    *bufio.Reader
+	count int
+}
+
+func newOverflow(size int, dir string) overflow {
+	return overflow{
+		size:    size,
+		dir:     dir,
+		backlog: make(map[string][]event),
+		pending: make(map[string]int),
+		signal:  make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+func (o *overflow) hasPending(topic string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.pending[topic] > 0
+}
+
+// sent marks a popped event of the topic as moved to the queue, or
+// discarded.
+func (o *overflow) sent(topic string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.pending[topic]--
+}
+
+func (o *overflow) len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	n := 0
+	for _, events := range o.backlog {
+		n += len(events)
+	}
+	if o.spill != nil {
+		n += o.spill.count
+	}
+	return n
+}
+
+func (o *overflow) notify() {
+	select {
+	case o.signal <- struct{}{}:
+	default:
+	}
+}
+
+// enqueue queues the event according to the overflow policy of its topic.
+// Unless wait is set, the Block policy fails instead of waiting.
+func (b *Bus) enqueue(ctx context.Context, evt event, wait bool) error {
+	b.closemu.RLock()
+	if b.closed {
+		b.closemu.RUnlock()
+		return ErrStopped
+	}
+	b.sending.Add(1)
+	b.closemu.RUnlock()
+	defer b.sending.Done()
+
+	ts := b.stats.topic(evt.topic)
+	accept := func() {
+		b.record(evt)
+		ts.queueDepth.Add(1)
+	}
+	if !b.overflow.hasPending(evt.topic) {
+		select {
+		case b.queue <- evt:
+			accept()
+			return nil
+		default:
+		}
+	}
+	switch b.overflowPolicy(evt.topic) {
+	case DropOldest:
+		accept()
+		b.pushBacklog(evt)
+		return nil
+	case SpillToDisk:
+		if err := b.spill(evt); err != nil {
+			ts.dropped.Add(1)
+			return err
+		}
+		accept()
+		return nil
+	case Block:
+		if wait {
+			select {
+			case b.queue <- evt:
+				accept()
+				return nil
+			case <-ctx.Done():
+				ts.dropped.Add(1)
+				return ctx.Err()
+			case <-b.stopping:
+				ts.dropped.Add(1)
+				return ErrStopped
+			}
+		}
+	}
+	ts.dropped.Add(1)
+	return ErrQueueFull
+}
+
+func (b *Bus) pushBacklog(evt event) {
+	o := &b.overflow
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	events := o.backlog[evt.topic]
+	// A topic is in order as long as it has a backlog, which trimming
+	// the oldest event below doesn't change.
+	if len(events) == 0 {
+		o.order = append(o.order, evt.topic)
+	}
+	if len(events) >= o.size {
+		ts := b.stats.topic(evt.topic)
+		ts.dropped.Add(1)
+		ts.queueDepth.Add(-1)
+		events = events[1:]
+		o.pending[evt.topic]--
+	}
+	o.backlog[evt.topic] = append(events, evt)
+	o.pending[evt.topic]++
+	o.notify()
+}
+
+func (b *Bus) spill(evt event) error {
+	payload, err := b.codec.Encode(evt.message)
+	if err != nil {
+		return err
+	}
+	env := Envelope{
+		ID:      EventID(evt.ctx),
+		Topic:   evt.topic,
+		Payload: payload,
+	}
+	if span := trace.SpanFromContext(evt.ctx); span != nil {
+		env.Traceparent = span.Context.Traceparent()
+	}
+	line, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	o := &b.overflow
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.spill == nil {
+		if err := os.MkdirAll(o.dir, 0o700); err != nil {
+			return err
+		}
+		w, err := os.CreateTemp(o.dir, "events-*.spill")
+		if err != nil {
+			return err
+		}
+		r, err := os.Open(w.Name())
+		if err != nil {
+			w.Close()
+			os.Remove(w.Name())
+			return err
+		}
+		o.spill = &spillFile{path: w.Name(), w: w, r: r, br: bufio.NewReader(r)}
+	}
+	if _, err := o.spill.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	o.spill.count++
+	o.pending[evt.topic]++
+	o.notify()
+	return nil
+}
+
+// next returns the next event of the backlogs or the spill file.
+func (b *Bus) next() (event, bool) {
+	for {
+		evt, env, ok := b.overflow.pop()
+		if !ok {
+			return event{}, false
+		}
+		if env == nil {
+			return evt, true
+		}
+		v, err := decodePayload(b.codec, env.Topic, env.Payload)
+		if err != nil {
+			b.stats.topic(env.Topic).queueDepth.Add(-1)
+			b.overflow.sent(env.Topic)
+			b.deadLettered(b.deadLetters.add(DeadLetter{Topic: env.Topic, Message: env.Payload, Err: err, FailedAt: time.Now()}))
+			slog.Error("event: failed to decode spilled payload", "topic", env.Topic, "err", err)
+			continue
+		}
+		ctx := context.Background()
+		if len(env.ID) > 0 {
+			ctx = context.WithValue(ctx, eventIDKey{}, env.ID)
+		}
+		if sc, err := trace.ParseTraceparent(env.Traceparent); err == nil {
+			ctx = trace.ContextWithRemote(ctx, sc)
+		}
+		return event{ctx: ctx, topic: env.Topic, message: v}, true
+	}
+}
+
+// pop removes the next event of the backlogs, or the next envelope of the
+// spill file, taking turns between them so neither starves. The event
+// stays pending until it is sent.
+func (o *overflow) pop() (event, *Envelope, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.turn++
+	if o.spill != nil && (o.turn%2 == 0 || len(o.order) == 0) {
+		env, err := o.readSpilled()
+		if err == nil {
+			return event{}, &env, true
+		}
+		slog.Error("event: failed to read spilled event", "path", o.spill.path, "err", err)
+		o.closeSpill()
+	}
+	if len(o.order) == 0 {
+		return event{}, nil, false
+	}
+	topic := o.order[0]
+	events := o.backlog[topic]
+	evt := events[0]
+	if len(events) == 1 {
+		delete(o.backlog, topic)
+		o.order = o.order[1:]
+	} else {
+		o.backlog[topic] = events[1:]
+		// Move the topic to the back, so every topic gets its turn.
+		o.order = append(o.order[1:], topic)
+	}
+	return evt, nil, true
+}
+
+func (o *overflow) readSpilled() (Envelope, error) {
+	var env Envelope
+	line, err := o.spill.br.ReadBytes('\n')
+	if err != nil {
+		return env, err
+	}
+	if err := json.Unmarshal(line, &env); err != nil {
+		return env, err
+	}
+	o.spill.count--
+	if o.spill.count == 0 {
+		o.closeSpill()
+	}
+	return env, nil
+}
+
+// closeSpill removes the spill file, a new one is created by the next spill.
+func (o *overflow) closeSpill() {
+	o.spill.w.Close()
+	o.spill.r.Close()
+	os.Remove(o.spill.path)
+	o.spill = nil
+}
+
+// drain moves the events of the overflow to the queue as soon as there
+// is room. Once the Bus is stopped it moves the remaining events and
+// returns.
+func (b *Bus) drain() {
+	defer close(b.overflow.done)
+	for {
+		evt, ok := b.next()
+		if !ok {
+			select {
+			case <-b.overflow.signal:
+				continue
+			case <-b.overflow.quit:
+			}
+			// Stop waits for drain before closing the queue and no more
+			// events are added, hence whatever is left can be moved.
+			for evt, ok := b.next(); ok; evt, ok = b.next() {
+				b.queue <- evt
+				b.overflow.sent(evt.topic)
+			}
+			return
+		}
+		b.queue <- evt
+		b.overflow.sent(evt.topic)
+	}
+}
@@ -0,0 +1,180 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stalled returns a bus whose handlers block until release is called,
+// with its queue filled up with events of the fill topic.
+func stalled(t *testing.T, opts Options) (bus *Bus, received func(topic string) []any, release func()) {
+	opts.BufferSize = 1
+	opts.Workers = 1
+	bus = NewBus(opts)
+	var (
+		mu     sync.Mutex
+		events = map[string][]any{}
+		ch     = make(chan struct{})
+		once   sync.Once
+	)
+	bus.Subscribe(">", func(ctx context.Context, v any) error {
+		<-ch
+		mu.Lock()
+		defer mu.Unlock()
+		topic := TopicFromContext(ctx)
+		events[topic] = append(events[topic], v)
+		return nil
+	})
+	// The queue is full once events keep being rejected, even after the
+	// dispatcher had the time to pick up the last one.
+	bus.SetOverflow("fill", Block)
+	for misses := 0; misses < 3; {
+		if bus.TryEmit(context.Background(), "fill", 0) {
+			misses = 0
+			continue
+		}
+		misses++
+		time.Sleep(5 * time.Millisecond)
+	}
+	received = func(topic string) []any {
+		mu.Lock()
+		defer mu.Unlock()
+		return events[topic]
+	}
+	release = func() { once.Do(func() { close(ch) }) }
+	t.Cleanup(func() {
+		release()
+		bus.Stop()
+	})
+	return bus, received, release
+}
+
+func TestTryEmit(t *testing.T) {
+	bus, _, _ := stalled(t, Options{})
+	if bus.TryEmit(context.Background(), "foo", 1) {
+		t.Fatal("expected TryEmit on a full queue to fail")
+	}
+	if dropped := bus.Stats()["foo"].Dropped; dropped != 1 {
+		t.Errorf("expected 1 dropped event got %d", dropped)
+	}
+}
+
+func TestEmitContextDeadline(t *testing.T) {
+	bus, _, _ := stalled(t, Options{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := bus.Emit(ctx, "foo", 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded got %v", err)
+	}
+	if dropped := bus.Stats()["foo"].Dropped; dropped != 1 {
+		t.Errorf("expected 1 dropped event got %d", dropped)
+	}
+}
+
+func TestOverflowDropNewest(t *testing.T) {
+	bus, received, release := stalled(t, Options{Overflow: DropNewest})
+	if err := bus.Emit(context.Background(), "foo", 1); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull got %v", err)
+	}
+	release()
+	bus.Stop()
+	if len(received("foo")) != 0 {
+		t.Errorf("expected the event to be dropped got %v", received("foo"))
+	}
+}
+
+func TestOverflowDropOldest(t *testing.T) {
+	bus, received, release := stalled(t, Options{OverflowSize: 2})
+	bus.SetOverflow("logs.>", DropOldest)
+	for i := range 5 {
+		if err := bus.Emit(context.Background(), "logs.app", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if depth := bus.Stats()["logs.app"].QueueDepth; depth != 2 {
+		t.Errorf("expected 2 queued events got %d", depth)
+	}
+	release()
+	bus.Stop()
+	if got := received("logs.app"); !slices.Equal(got, []any{3, 4}) {
+		t.Errorf("expected the newest events got %v", got)
+	}
+	if dropped := bus.Stats()["logs.app"].Dropped; dropped != 3 {
+		t.Errorf("expected 3 dropped events got %d", dropped)
+	}
+}
+
+func TestOverflowSpillToDisk(t *testing.T) {
+	RegisterPayload[int]("spill")
+	dir := t.TempDir()
+	bus, received, release := stalled(t, Options{Overflow: SpillToDisk, SpillDir: dir})
+	for i := range 5 {
+		if err := bus.Emit(context.Background(), "spill", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 1 {
+		t.Errorf("expected a spill file got %d files", len(files))
+	}
+	if depth := bus.QueueDepth(); depth < 5 {
+		t.Errorf("expected at least 5 queued events got %d", depth)
+	}
+	release()
+	bus.Stop()
+	if got := received("spill"); !slices.Equal(got, []any{0, 1, 2, 3, 4}) {
+		t.Errorf("expected all spilled events in order got %v", got)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("expected the spill file to be removed got %d files", len(files))
+	}
+}
+
+func TestOverflowPendingUntilQueued(t *testing.T) {
+	bus, received, release := stalled(t, Options{Overflow: DropOldest})
+	if err := bus.Emit(context.Background(), "foo", 1); err != nil {
+		t.Fatal(err)
+	}
+	// Wait for drain to pop the event, it can't queue it while the queue
+	// is full.
+	for bus.overflow.len() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if !bus.overflow.hasPending("foo") {
+		t.Fatal("expected the popped event to be pending until it is queued")
+	}
+	if err := bus.Emit(context.Background(), "foo", 2); err != nil {
+		t.Fatal(err)
+	}
+	release()
+	bus.Stop()
+	if got := received("foo"); !slices.Equal(got, []any{1, 2}) {
+		t.Errorf("expected the events in order got %v", got)
+	}
+	if bus.overflow.hasPending("foo") {
+		t.Error("expected no pending events after Stop")
+	}
+}
+
+func TestOverflowDropOldestSizeOne(t *testing.T) {
+	bus, received, release := stalled(t, Options{Overflow: DropOldest, OverflowSize: 1})
+	for i := range 3 {
+		if err := bus.Emit(context.Background(), "foo", i); err != nil {
+			t.Fatal(err)
+		}
+		// drain holds the first event until the queue has room, the
+		// others replace each other in the backlog.
+		for i == 0 && bus.overflow.len() > 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	release()
+	bus.Stop()
+	if got := received("foo"); !slices.Equal(got, []any{0, 2}) {
+		t.Errorf("expected the first and newest event got %v", got)
+	}
+}
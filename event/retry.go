@@ -0,0 +1,81 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"time"
+)
+
+// RetryPolicy configures how often and when failed handlers are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// A value of 1 or less disables retries.
+	MaxAttempts int
+	// Backoff is the delay before the first retry.
+	Backoff time.Duration
+	// Multiplier is the factor the delay grows with after each retry.
+	Multiplier float64
+	// MaxBackoff caps the delay between retries.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is used for subscriptions without their own policy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     100 * time.Millisecond,
+	Multiplier:  2,
+	MaxBackoff:  10 * time.Second,
+}
+
+// NoRetry disables retries, failed handlers are dead lettered right away.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// UseRetryPolicy sets the retry policy of the default Bus, used for
+// subscriptions without their own policy.
+func UseRetryPolicy(p RetryPolicy) {
+	Default().UseRetryPolicy(p)
+}
+
+// WithRetry overrides the retry policy of a single subscription.
+//
+//	event.Subscribe("auth.signup", sendWelcomeEmail, event.WithRetry(event.NoRetry))
+func WithRetry(p RetryPolicy) SubscribeOption {
+	return func(sub *Subscription) {
+		sub.retry = &p
+	}
+}
+
+// delay returns the delay before the given retry, starting at 1.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	d := time.Duration(float64(p.Backoff) * math.Pow(multiplier, float64(retry-1)))
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// PanicError is the error reported for a handler that panicked.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("event handler panic: %v", e.Value)
+}
+
+// call calls the handler, converting a panic into a *PanicError.
+func call(h HandlerFunc, ctx context.Context, v any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return h(ctx, v)
+}
@@ -0,0 +1,112 @@
+package event
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Scheduled is an event that is emitted at a later time.
+type Scheduled struct {
+	ID    string
+	Topic string
+	At    time.Time
+
+	bus   *Bus
+	timer *time.Timer
+}
+
+// Cancel cancels the emission. It returns false if the event was already
+// emitted or canceled.
+func (s *Scheduled) Cancel() bool {
+	return s.bus.CancelScheduled(s.ID)
+}
+
+// EmitAfter emits the event to the given topic on the default Bus once
+// the duration passed, see Bus.EmitAt.
+//
+//	reminder := event.EmitAfter(24*time.Hour, "auth.verification.reminder", user)
+//	...
+//	reminder.Cancel()
+func EmitAfter(d time.Duration, topic string, event any) *Scheduled {
+	return Default().EmitAt(context.Background(), time.Now().Add(d), topic, event)
+}
+
+// EmitAt emits the event to the given topic on the default Bus at the
+// given time, see Bus.EmitAt.
+func EmitAt(t time.Time, topic string, event any) *Scheduled {
+	return Default().EmitAt(context.Background(), t, topic, event)
+}
+
+// CancelScheduled cancels the scheduled event of the default Bus with the
+// given ID. It returns false if there is no such pending event.
+func CancelScheduled(id string) bool {
+	return Default().CancelScheduled(id)
+}
+
+// EmitAt emits the event to the given topic at the given time, or right
+// away if the time is in the past. Pending events only live in memory and
+// are dropped when the Bus is stopped, use a Scheduler for events that
+// have to survive restarts.
+func (b *Bus) EmitAt(ctx context.Context, t time.Time, topic string, v any) *Scheduled {
+	ctx = detach(ctx)
+	s := &Scheduled{
+		ID:    newEventID(),
+		Topic: topic,
+		At:    t,
+		bus:   b,
+	}
+	b.schedmu.Lock()
+	defer b.schedmu.Unlock()
+	if b.scheduled == nil {
+		b.scheduled = make(map[string]*Scheduled)
+	}
+	b.scheduled[s.ID] = s
+	s.timer = time.AfterFunc(time.Until(t), func() {
+		b.schedmu.Lock()
+		_, pending := b.scheduled[s.ID]
+		delete(b.scheduled, s.ID)
+		b.schedmu.Unlock()
+		if pending {
+			if err := b.Emit(ctx, topic, v); err != nil {
+				slog.Warn("event: dropped scheduled event", "topic", topic, "err", err)
+			}
+		}
+	})
+	return s
+}
+
+// CancelScheduled cancels the scheduled event with the given ID. It
+// returns false if there is no such pending event.
+func (b *Bus) CancelScheduled(id string) bool {
+	b.schedmu.Lock()
+	defer b.schedmu.Unlock()
+	s, ok := b.scheduled[id]
+	if !ok {
+		return false
+	}
+	s.timer.Stop()
+	delete(b.scheduled, id)
+	return true
+}
+
+// Pending returns the scheduled events that have not been emitted yet.
+func (b *Bus) Pending() []*Scheduled {
+	b.schedmu.Lock()
+	defer b.schedmu.Unlock()
+	pending := make([]*Scheduled, 0, len(b.scheduled))
+	for _, s := range b.scheduled {
+		pending = append(pending, s)
+	}
+	return pending
+}
+
+// cancelAllScheduled drops all pending events.
+func (b *Bus) cancelAllScheduled() {
+	b.schedmu.Lock()
+	defer b.schedmu.Unlock()
+	for id, s := range b.scheduled {
+		s.timer.Stop()
+		delete(b.scheduled, id)
+	}
+}
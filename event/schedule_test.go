@@ -0,0 +1,105 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestEmitAfter(t *testing.T) {
+	bus := NewBus(Options{})
+	defer bus.Stop()
+
+	handled := make(chan any, 2)
+	bus.Subscribe("reminder", func(_ context.Context, v any) error {
+		handled <- v
+		return nil
+	})
+
+	start := time.Now()
+	bus.EmitAt(context.Background(), start.Add(20*time.Millisecond), "reminder", 1)
+	canceled := bus.EmitAt(context.Background(), start.Add(10*time.Millisecond), "reminder", 2)
+	if len(bus.Pending()) != 2 {
+		t.Errorf("expected 2 pending events got %d", len(bus.Pending()))
+	}
+	if !canceled.Cancel() {
+		t.Errorf("expected pending event to be canceled")
+	}
+	if canceled.Cancel() {
+		t.Errorf("expected a canceled event not to be canceled again")
+	}
+
+	if v := <-handled; v != 1 {
+		t.Errorf("expected 1 got %v", v)
+	}
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("expected event to be emitted after 20ms got %s", d)
+	}
+	select {
+	case v := <-handled:
+		t.Errorf("expected canceled event not to be emitted got %v", v)
+	case <-time.After(20 * time.Millisecond):
+	}
+	if len(bus.Pending()) != 0 {
+		t.Errorf("expected no pending events got %d", len(bus.Pending()))
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`create table event_schedule(
+		id text primary key,
+		topic text not null,
+		payload text not null,
+		emit_at datetime not null,
+		created_at datetime not null
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := NewBus(Options{})
+	defer bus.Stop()
+	scheduler := &Scheduler{DB: db, Bus: bus}
+	ctx := context.Background()
+
+	type trial struct{ UserID int }
+	topic := NewTopic[trial]("trial.expired")
+	var expired []trial
+	bus.Subscribe(topic.Name(), func(_ context.Context, v any) error {
+		expired = append(expired, v.(trial))
+		return nil
+	})
+
+	if _, err := scheduler.EmitAt(ctx, db, time.Now().Add(-time.Second), topic.Name(), trial{UserID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scheduler.EmitAfter(ctx, db, time.Hour, topic.Name(), trial{UserID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	id, err := scheduler.EmitAt(ctx, db, time.Now().Add(-time.Second), topic.Name(), trial{UserID: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := scheduler.Cancel(ctx, db, id); !ok || err != nil {
+		t.Fatalf("expected event to be canceled got %v %v", ok, err)
+	}
+
+	n, err := scheduler.Process(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || len(expired) != 1 || expired[0].UserID != 1 {
+		t.Errorf("expected only the due event to be emitted got %d %v", n, expired)
+	}
+	var pending int
+	db.QueryRow("select count(*) from event_schedule").Scan(&pending)
+	if pending != 1 {
+		t.Errorf("expected 1 pending event got %d", pending)
+	}
+}
@@ -0,0 +1,126 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Scheduler persists scheduled events in a SQL table, so they survive
+// restarts. Run emits the events that are due.
+//
+// The table is expected to have the following layout:
+//
+//	create table if not exists event_schedule(
+//		id text primary key,
+//		topic text not null,
+//		payload text not null,
+//		emit_at datetime not null,
+//		created_at datetime not null
+//	);
+//
+// Like the Outbox, events are emitted at least once and the payloads are
+// encoded as JSON.
+type Scheduler struct {
+	DB *sql.DB
+	// Table defaults to event_schedule.
+	Table string
+	// Bus the events are emitted on. Defaults to the default Bus.
+	Bus *Bus
+	// PollInterval is the time between looking for due events.
+	// Defaults to 1 second.
+	PollInterval time.Duration
+	// BatchSize is the maximum number of events emitted per poll.
+	// Defaults to 100.
+	BatchSize int
+}
+
+// EmitAfter stores the event to be emitted once the duration passed and
+// returns its ID, see EmitAt.
+func (s *Scheduler) EmitAfter(ctx context.Context, tx Execer, d time.Duration, topic string, v any) (string, error) {
+	return s.EmitAt(ctx, tx, time.Now().Add(d), topic, v)
+}
+
+// EmitAt stores the event to be emitted at the given time using the given
+// Execer, which can be the transaction of the change the event belongs to.
+// The returned ID can be used to cancel the event.
+//
+//	id, err := scheduler.EmitAt(ctx, db, user.TrialEndsAt, "billing.trial.expired", user)
+func (s *Scheduler) EmitAt(ctx context.Context, tx Execer, t time.Time, topic string, v any) (string, error) {
+	payload, err := JSONCodec.Encode(v)
+	if err != nil {
+		return "", fmt.Errorf("event: failed to encode scheduled payload of topic %s: %w", topic, err)
+	}
+	id := newEventID()
+	query := fmt.Sprintf("insert into %s (id, topic, payload, emit_at, created_at) values (?, ?, ?, ?, ?)", s.table())
+	if _, err := tx.ExecContext(ctx, query, id, topic, string(payload), t.UTC(), time.Now().UTC()); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Cancel deletes the scheduled event with the given ID. It returns false
+// if the event was already emitted or canceled.
+func (s *Scheduler) Cancel(ctx context.Context, tx Execer, id string) (bool, error) {
+	query := fmt.Sprintf("delete from %s where id = ?", s.table())
+	res, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Run emits the due events until ctx is done. Only run a single
+// Scheduler per table.
+//
+//	go scheduler.Run(ctx)
+func (s *Scheduler) Run(ctx context.Context) error {
+	interval := s.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Process(ctx); err != nil {
+			slog.Error("event: scheduler failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Process emits a single batch of due events, which are handled before
+// they are deleted, and returns the number of emitted events.
+func (s *Scheduler) Process(ctx context.Context) (int, error) {
+	batchSize := s.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	query := fmt.Sprintf("select id, topic, payload from %s where emit_at <= ? order by emit_at, id limit ?", s.table())
+	del := fmt.Sprintf("delete from %s where id = ?", s.table())
+	stored := storedEvents{
+		db:      s.DB,
+		bus:     s.Bus,
+		source:  "scheduled",
+		emitted: true,
+		done: func(ctx context.Context, id string) error {
+			_, err := s.DB.ExecContext(ctx, del, id)
+			return err
+		},
+	}
+	return stored.process(ctx, query, time.Now().UTC(), batchSize)
+}
+
+func (s *Scheduler) table() string {
+	if len(s.Table) == 0 {
+		return "event_schedule"
+	}
+	return s.Table
+}
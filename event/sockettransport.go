@@ -0,0 +1,289 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// SocketTransport is a Transport for processes on a single host. Every
+// process listens on its own Unix socket in a shared directory and
+// publishes events to the sockets of all the others.
+//
+//	transport, err := event.NewSocketTransport("/tmp/myapp-events")
+//	bus := event.NewBus(event.Options{Transport: transport})
+type SocketTransport struct {
+	dir      string
+	path     string
+	listener net.Listener
+	ch       chan Envelope
+	quitch   chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+
+	// mu guards closed and the peers and accepted conns. Publishers
+	// register with publishing under it, Close waits for them before
+	// closing ch.
+	mu         sync.Mutex
+	closed     bool
+	peers      map[string]*peer
+	conns      map[net.Conn]struct{}
+	publishing sync.WaitGroup
+}
+
+// peer is the connection to the socket of another process. Its mu
+// serializes the writes to conn, conn is only set while holding both
+// its mu and the mu of the transport, so Close can close it while a
+// write is blocked.
+type peer struct {
+	mu   sync.Mutex
+	conn net.Conn
+	enc  *json.Encoder
+}
+
+// NewSocketTransport creates the directory if needed and starts listening
+// on a socket in it named after the process ID.
+func NewSocketTransport(dir string) (*SocketTransport, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%d-%s.sock", os.Getpid(), newEventID()[:8]))
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	t := &SocketTransport{
+		dir:      dir,
+		path:     path,
+		listener: listener,
+		ch:       make(chan Envelope, defaultBufferSize),
+		quitch:   make(chan struct{}),
+		peers:    make(map[string]*peer),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	t.wg.Add(1)
+	go t.accept()
+	return t, nil
+}
+
+// Publish implements Transport. Sockets of processes that are gone are
+// removed from the directory. Writes to a peer that doesn't read are
+// abandoned once ctx is done.
+func (t *SocketTransport) Publish(ctx context.Context, env Envelope) error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return ErrTransportClosed
+	}
+	t.publishing.Add(1)
+	t.mu.Unlock()
+	defer t.publishing.Done()
+
+	paths, err := filepath.Glob(filepath.Join(t.dir, "*.sock"))
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, path := range paths {
+		if path == t.path {
+			continue
+		}
+		if err := t.send(ctx, path, env); err != nil {
+			if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, os.ErrNotExist) {
+				os.Remove(path)
+				continue
+			}
+			if errors.Is(err, ErrTransportClosed) {
+				return err
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(path), err))
+		}
+	}
+	// Deliver to this process as well, like all other transports.
+	select {
+	case t.ch <- env:
+	case <-t.quitch:
+		return ErrTransportClosed
+	case <-ctx.Done():
+		errs = append(errs, ctx.Err())
+	}
+	return errors.Join(errs...)
+}
+
+// peer returns the peer for the socket at path.
+func (t *SocketTransport) peer(path string) (*peer, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, ErrTransportClosed
+	}
+	p, ok := t.peers[path]
+	if !ok {
+		p = &peer{}
+		t.peers[path] = p
+	}
+	return p, nil
+}
+
+// setConn sets the connection of the peer, which must be locked. It
+// closes conn instead if the transport is closed.
+func (t *SocketTransport) setConn(p *peer, conn net.Conn) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed && conn != nil {
+		conn.Close()
+		return ErrTransportClosed
+	}
+	p.conn = conn
+	p.enc = nil
+	if conn != nil {
+		p.enc = json.NewEncoder(conn)
+	}
+	return nil
+}
+
+// send writes the envelope to the peer, reconnecting once when the
+// connection broke.
+func (t *SocketTransport) send(ctx context.Context, path string, env Envelope) error {
+	p, err := t.peer(path)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for attempt := 0; ; attempt++ {
+		if p.conn == nil {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, "unix", path)
+			if err != nil {
+				t.mu.Lock()
+				if t.peers[path] == p {
+					delete(t.peers, path)
+				}
+				t.mu.Unlock()
+				return err
+			}
+			if err := t.setConn(p, conn); err != nil {
+				return err
+			}
+		}
+		err := write(ctx, p, env)
+		if err == nil {
+			return nil
+		}
+		// A partial write leaves the stream unusable, start over with a
+		// new connection.
+		p.conn.Close()
+		t.setConn(p, nil)
+		if attempt > 0 || ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// write encodes the envelope to the connection of the peer, with a write
+// deadline following ctx.
+func write(ctx context.Context, p *peer, env Envelope) error {
+	deadline, _ := ctx.Deadline()
+	if err := p.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	conn := p.conn
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetWriteDeadline(time.Now())
+	})
+	defer stop()
+	if err := p.enc.Encode(env); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
+
+// Receive implements Transport.
+func (t *SocketTransport) Receive() <-chan Envelope {
+	return t.ch
+}
+
+// Close implements Transport. It removes the socket of this process.
+// Writes still in progress fail as their connection is closed.
+func (t *SocketTransport) Close() error {
+	var err error
+	t.once.Do(func() {
+		close(t.quitch)
+		err = t.listener.Close()
+		t.mu.Lock()
+		t.closed = true
+		for _, p := range t.peers {
+			if p.conn != nil {
+				p.conn.Close()
+			}
+		}
+		for conn := range t.conns {
+			conn.Close()
+		}
+		t.mu.Unlock()
+		t.wg.Wait()
+		t.publishing.Wait()
+		close(t.ch)
+	})
+	return err
+}
+
+func (t *SocketTransport) accept() {
+	defer t.wg.Done()
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			select {
+			case <-t.quitch:
+			default:
+				slog.Error("event: socket transport stopped accepting connections", "err", err)
+			}
+			return
+		}
+		t.mu.Lock()
+		if t.closed {
+			// Close already closed the tracked connections.
+			t.mu.Unlock()
+			conn.Close()
+			return
+		}
+		t.conns[conn] = struct{}{}
+		t.wg.Add(1)
+		t.mu.Unlock()
+		go t.read(conn)
+	}
+}
+
+func (t *SocketTransport) read(conn net.Conn) {
+	defer t.wg.Done()
+	defer func() {
+		conn.Close()
+		t.mu.Lock()
+		delete(t.conns, conn)
+		t.mu.Unlock()
+	}()
+	dec := json.NewDecoder(conn)
+	for {
+		var env Envelope
+		if err := dec.Decode(&env); err != nil {
+			return
+		}
+		select {
+		case t.ch <- env:
+		case <-t.quitch:
+			return
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SQLTransportConfig configures a SQLTransport.
+type SQLTransportConfig struct {
+	// Table defaults to event_transport.
+	Table string
+	// PollInterval is the time between looking for new events.
+	// Defaults to 500 milliseconds.
+	PollInterval time.Duration
+	// Retention is how long events are kept before they are deleted.
+	// It should be well above the poll interval. Defaults to 1 hour.
+	Retention time.Duration
+}
+
+// SQLTransport is a Transport for processes sharing a database, like
+// multiple app instances behind a load balancer. Published events are
+// inserted into a table that every process polls for new rows.
+//
+// The table is expected to have the following layout (sqlite3):
+//
+//	create table if not exists event_transport(
+//		seq integer primary key autoincrement,
+//		id text not null,
+//		topic text not null,
+//		payload blob not null,
+//		traceparent text not null,
+//		created_at datetime not null
+//	);
+//
+// A process only receives the events published after it started.
+type SQLTransport struct {
+	db     *sql.DB
+	config SQLTransportConfig
+	ch     chan Envelope
+	quitch chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewSQLTransport returns a SQLTransport that starts polling right away.
+func NewSQLTransport(db *sql.DB, config SQLTransportConfig) (*SQLTransport, error) {
+	if len(config.Table) == 0 {
+		config.Table = "event_transport"
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 500 * time.Millisecond
+	}
+	if config.Retention <= 0 {
+		config.Retention = time.Hour
+	}
+	t := &SQLTransport{
+		db:     db,
+		config: config,
+		ch:     make(chan Envelope, defaultBufferSize),
+		quitch: make(chan struct{}),
+	}
+	var seq int64
+	query := fmt.Sprintf("select coalesce(max(seq), 0) from %s", config.Table)
+	if err := db.QueryRow(query).Scan(&seq); err != nil {
+		return nil, err
+	}
+	t.wg.Add(1)
+	go t.poll(seq)
+	return t, nil
+}
+
+// Publish implements Transport.
+func (t *SQLTransport) Publish(ctx context.Context, env Envelope) error {
+	query := fmt.Sprintf("insert into %s (id, topic, payload, traceparent, created_at) values (?, ?, ?, ?, ?)", t.config.Table)
+	_, err := t.db.ExecContext(ctx, query, env.ID, env.Topic, env.Payload, env.Traceparent, time.Now().UTC())
+	return err
+}
+
+// Receive implements Transport.
+func (t *SQLTransport) Receive() <-chan Envelope {
+	return t.ch
+}
+
+// Close implements Transport.
+func (t *SQLTransport) Close() error {
+	t.once.Do(func() {
+		close(t.quitch)
+		t.wg.Wait()
+		close(t.ch)
+	})
+	return nil
+}
+
+func (t *SQLTransport) poll(seq int64) {
+	defer t.wg.Done()
+	ticker := time.NewTicker(t.config.PollInterval)
+	defer ticker.Stop()
+	lastCleanup := time.Now()
+	for {
+		select {
+		case <-t.quitch:
+			return
+		case <-ticker.C:
+		}
+		var err error
+		seq, err = t.fetch(seq)
+		if err != nil {
+			slog.Error("event: sql transport failed to fetch events", "err", err)
+		}
+		if time.Since(lastCleanup) > t.config.Retention/2 {
+			lastCleanup = time.Now()
+			query := fmt.Sprintf("delete from %s where created_at < ?", t.config.Table)
+			if _, err := t.db.Exec(query, time.Now().UTC().Add(-t.config.Retention)); err != nil {
+				slog.Error("event: sql transport cleanup failed", "err", err)
+			}
+		}
+	}
+}
+
+// fetch sends the events after seq to the receive channel and returns
+// the sequence number of the last one.
+func (t *SQLTransport) fetch(seq int64) (int64, error) {
+	query := fmt.Sprintf("select seq, id, topic, payload, traceparent from %s where seq > ? order by seq", t.config.Table)
+	rows, err := t.db.Query(query, seq)
+	if err != nil {
+		return seq, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var env Envelope
+		var next int64
+		if err := rows.Scan(&next, &env.ID, &env.Topic, &env.Payload, &env.Traceparent); err != nil {
+			return seq, err
+		}
+		select {
+		case t.ch <- env:
+			seq = next
+		case <-t.quitch:
+			return seq, nil
+		}
+	}
+	return seq, rows.Err()
+}
@@ -0,0 +1,165 @@
+package event
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Hooks are called by a Bus to observe its events, for example to export
+// metrics. All fields are optional.
+type Hooks struct {
+	// OnEmit is called for every event emitted on the Bus.
+	OnEmit func(topic string)
+	// OnHandled is called after every attempt of a handler, with its
+	// duration and the error it returned.
+	OnHandled func(topic string, d time.Duration, err error)
+	// OnDeadLetter is called when an event is dead lettered.
+	OnDeadLetter func(dl DeadLetter)
+}
+
+// AddHooks registers hooks that are called for the events of every Bus.
+func AddHooks(h Hooks) {
+	globalmu.Lock()
+	defer globalmu.Unlock()
+	globalHooks = append(globalHooks, h)
+}
+
+// Stats returns the statistics of the default Bus, see Bus.Stats.
+func Stats() map[string]TopicStats {
+	return Default().Stats()
+}
+
+// TopicStats holds the statistics of a single topic since the Bus was created.
+type TopicStats struct {
+	// Emitted is the number of emitted events.
+	Emitted uint64
+	// Handled is the number of successful handler attempts.
+	Handled uint64
+	// Failed is the number of failed handler attempts, including the ones
+	// that were retried.
+	Failed uint64
+	// DeadLettered is the number of events that exhausted their attempts.
+	DeadLettered uint64
+	// Dropped is the number of events dropped because the queue was full,
+	// see OverflowPolicy.
+	Dropped uint64
+	// QueueDepth is the number of events and deliveries to subscribers
+	// waiting to be handled.
+	QueueDepth int64
+	// Latency is the time spent in handlers, in total.
+	Latency time.Duration
+}
+
+// MeanLatency returns the average duration of a handler attempt.
+func (s TopicStats) MeanLatency() time.Duration {
+	if n := s.Handled + s.Failed; n > 0 {
+		return s.Latency / time.Duration(n)
+	}
+	return 0
+}
+
+type topicStats struct {
+	emitted      atomic.Uint64
+	handled      atomic.Uint64
+	failed       atomic.Uint64
+	deadLettered atomic.Uint64
+	dropped      atomic.Uint64
+	queueDepth   atomic.Int64
+	latency      atomic.Int64
+}
+
+type stats struct {
+	mu     sync.RWMutex
+	topics map[string]*topicStats
+}
+
+func (s *stats) topic(topic string) *topicStats {
+	s.mu.RLock()
+	ts, ok := s.topics[topic]
+	s.mu.RUnlock()
+	if ok {
+		return ts
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ts, ok = s.topics[topic]; !ok {
+		if s.topics == nil {
+			s.topics = make(map[string]*topicStats)
+		}
+		ts = &topicStats{}
+		s.topics[topic] = ts
+	}
+	return ts
+}
+
+// Stats returns the statistics of every topic events were emitted to
+// or received on.
+func (b *Bus) Stats() map[string]TopicStats {
+	b.stats.mu.RLock()
+	defer b.stats.mu.RUnlock()
+	m := make(map[string]TopicStats, len(b.stats.topics))
+	for topic, ts := range b.stats.topics {
+		m[topic] = TopicStats{
+			Emitted:      ts.emitted.Load(),
+			Handled:      ts.handled.Load(),
+			Failed:       ts.failed.Load(),
+			DeadLettered: ts.deadLettered.Load(),
+			Dropped:      ts.dropped.Load(),
+			QueueDepth:   ts.queueDepth.Load(),
+			Latency:      time.Duration(ts.latency.Load()),
+		}
+	}
+	return m
+}
+
+// AddHooks registers hooks that are called for the events of the Bus.
+func (b *Bus) AddHooks(h Hooks) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.hooks = append(b.hooks, h)
+}
+
+func (b *Bus) emitted(topic string) {
+	b.stats.topic(topic).emitted.Add(1)
+	for _, h := range b.allHooks() {
+		if h.OnEmit != nil {
+			h.OnEmit(topic)
+		}
+	}
+}
+
+func (b *Bus) handled(topic string, d time.Duration, err error, hooks []Hooks) {
+	ts := b.stats.topic(topic)
+	ts.latency.Add(int64(d))
+	if err != nil {
+		ts.failed.Add(1)
+	} else {
+		ts.handled.Add(1)
+	}
+	for _, h := range hooks {
+		if h.OnHandled != nil {
+			h.OnHandled(topic, d, err)
+		}
+	}
+}
+
+func (b *Bus) deadLettered(dl DeadLetter) {
+	b.stats.topic(dl.Topic).deadLettered.Add(1)
+	for _, h := range b.allHooks() {
+		if h.OnDeadLetter != nil {
+			h.OnDeadLetter(dl)
+		}
+	}
+}
+
+// allHooks returns the global hooks followed by the hooks of the Bus.
+func (b *Bus) allHooks() []Hooks {
+	globalmu.RLock()
+	hooks := slices.Clone(globalHooks)
+	globalmu.RUnlock()
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return append(hooks, b.hooks...)
+}
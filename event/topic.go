@@ -0,0 +1,67 @@
+package event
+
+import (
+	"context"
+	"fmt"
+)
+
+// Topic is a topic whose events are of type T. Emitting or subscribing
+// with the wrong type fails at compile time instead of at runtime.
+//
+//	var UserSignup = event.NewTopic[User]("auth.signup")
+//
+//	UserSignup.Subscribe(func(ctx context.Context, user User) error {
+//		return sendWelcomeEmail(ctx, user)
+//	})
+//	UserSignup.Emit(ctx, user)
+//
+// A Topic shares the stream with the string based API, Emit(UserSignup.Name(), user)
+// reaches the same subscribers.
+type Topic[T any] struct {
+	name string
+}
+
+// NewTopic returns a Topic with the given name. Its payload type is
+// registered for the Outbox, see RegisterPayload.
+func NewTopic[T any](name string) Topic[T] {
+	RegisterPayload[T](name)
+	return Topic[T]{name: name}
+}
+
+// Name returns the name of the topic.
+func (t Topic[T]) Name() string {
+	return t.name
+}
+
+// Emit emits the event to the topic, see EmitContext.
+func (t Topic[T]) Emit(ctx context.Context, event T) error {
+	return EmitContext(ctx, t.name, event)
+}
+
+// TryEmit emits the event to the topic without blocking, see TryEmit.
+func (t Topic[T]) TryEmit(event T) bool {
+	return TryEmit(t.name, event)
+}
+
+// EmitSync calls the handlers of the topic inline, see EmitSync.
+func (t Topic[T]) EmitSync(ctx context.Context, event T) error {
+	return EmitSync(ctx, t.name, event)
+}
+
+// EmitOutbox stores the event in the outbox using tx, see Outbox.Emit.
+func (t Topic[T]) EmitOutbox(ctx context.Context, o *Outbox, tx Execer, event T) error {
+	return o.Emit(ctx, tx, t.name, event)
+}
+
+// Subscribe subscribes a typed handler to the topic. Events of another
+// type, emitted through the string based API, fail the handler with an
+// error and end up as dead letters.
+func (t Topic[T]) Subscribe(h func(context.Context, T) error, opts ...SubscribeOption) Subscription {
+	return Subscribe(t.name, func(ctx context.Context, event any) error {
+		v, ok := event.(T)
+		if !ok {
+			return fmt.Errorf("event: topic %s expects %T got %T", t.name, *new(T), event)
+		}
+		return h(ctx, v)
+	}, opts...)
+}
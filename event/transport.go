@@ -0,0 +1,82 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrTransportClosed is returned when publishing on a closed Transport.
+var ErrTransportClosed = errors.New("event: transport closed")
+
+// Envelope is an encoded event as it is sent over a Transport.
+type Envelope struct {
+	// ID is unique per event, see EventID.
+	ID    string
+	Topic string
+	// Payload is the event encoded with the Codec of the Bus.
+	Payload []byte
+	// Traceparent is the W3C traceparent of the span that emitted the
+	// event, so the trace continues in the receiving process.
+	Traceparent string
+}
+
+// Transport delivers the events emitted on a Bus to the buses of all
+// processes sharing the transport, including the emitting one. Without a
+// Transport a Bus delivers its events in memory, to its own process only.
+//
+// Only the payload and the trace are carried to other processes, values
+// registered with PropagateValues are not.
+type Transport interface {
+	// Publish sends the envelope to all processes.
+	Publish(ctx context.Context, env Envelope) error
+	// Receive returns the channel envelopes of all processes are received
+	// on. The channel is closed once the transport is closed.
+	Receive() <-chan Envelope
+	// Close stops the transport and closes the receive channel.
+	Close() error
+}
+
+// MemoryTransport is a Transport within a single process. It is mostly
+// useful to test that events survive being encoded.
+type MemoryTransport struct {
+	mu     sync.RWMutex
+	closed bool
+	ch     chan Envelope
+}
+
+// NewMemoryTransport returns a MemoryTransport buffering up to size envelopes.
+func NewMemoryTransport(size int) *MemoryTransport {
+	return &MemoryTransport{ch: make(chan Envelope, size)}
+}
+
+// Publish implements Transport.
+func (t *MemoryTransport) Publish(ctx context.Context, env Envelope) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return ErrTransportClosed
+	}
+	select {
+	case t.ch <- env:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Receive implements Transport.
+func (t *MemoryTransport) Receive() <-chan Envelope {
+	return t.ch
+}
+
+// Close implements Transport.
+func (t *MemoryTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.closed {
+		t.closed = true
+		close(t.ch)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anthdm/superkit/kit/trace"
+)
+
+type transportPayload struct {
+	Name string
+	Age  int
+}
+
+func init() {
+	RegisterPayload[transportPayload]("transport.user")
+}
+
+// receiveOne subscribes to transport.user on the bus and returns the
+// channel the handled payloads and event IDs are sent on.
+func receiveOne(bus *Bus) chan [2]any {
+	ch := make(chan [2]any, 4)
+	bus.Subscribe("transport.user", func(ctx context.Context, event any) error {
+		ch <- [2]any{event, EventID(ctx)}
+		return nil
+	})
+	return ch
+}
+
+func expectReceived(t *testing.T, ch chan [2]any) string {
+	t.Helper()
+	select {
+	case got := <-ch:
+		if got[0] != (transportPayload{Name: "bob", Age: 42}) {
+			t.Errorf("expected decoded payload got %#v", got[0])
+		}
+		return got[1].(string)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return ""
+}
+
+func TestMemoryTransport(t *testing.T) {
+	bus := NewBus(Options{Transport: NewMemoryTransport(8)})
+	ch := receiveOne(bus)
+	bus.Emit(context.Background(), "transport.user", transportPayload{Name: "bob", Age: 42})
+	if id := expectReceived(t, ch); len(id) != 32 {
+		t.Errorf("expected event ID got %q", id)
+	}
+	bus.Stop()
+}
+
+func TestMemoryTransportTrace(t *testing.T) {
+	recorder := &traceRecorder{}
+	trace.UseExporter(recorder)
+	defer trace.UseExporter(nil)
+
+	bus := NewBus(Options{Transport: NewMemoryTransport(8)})
+	done := make(chan trace.SpanContext, 1)
+	bus.Subscribe("transport.trace", func(ctx context.Context, _ any) error {
+		done <- trace.SpanFromContext(ctx).Context
+		return nil
+	})
+	ctx, span := trace.Start(context.Background(), "emit")
+	bus.Emit(ctx, "transport.trace", 1)
+	span.End()
+	if got := <-done; got.TraceID != span.Context.TraceID {
+		t.Errorf("expected trace %s to continue got %s", span.Context.TraceID, got.TraceID)
+	}
+	bus.Stop()
+}
+
+type traceRecorder struct{}
+
+func (traceRecorder) Export(*trace.Span) error { return nil }
+
+func TestSQLTransport(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:"+t.TempDir()+"/events.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	_, err = db.Exec(`create table event_transport(
+		seq integer primary key autoincrement,
+		id text not null,
+		topic text not null,
+		payload blob not null,
+		traceparent text not null,
+		created_at datetime not null
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two buses sharing a database act like two processes.
+	var chs []chan [2]any
+	var buses []*Bus
+	for range 2 {
+		transport, err := NewSQLTransport(db, SQLTransportConfig{PollInterval: 10 * time.Millisecond})
+		if err != nil {
+			t.Fatal(err)
+		}
+		bus := NewBus(Options{Transport: transport})
+		defer bus.Stop()
+		buses = append(buses, bus)
+		chs = append(chs, receiveOne(bus))
+	}
+	buses[0].Emit(context.Background(), "transport.user", transportPayload{Name: "bob", Age: 42})
+	if a, b := expectReceived(t, chs[0]), expectReceived(t, chs[1]); a != b {
+		t.Errorf("expected the same event ID in both processes got %s and %s", a, b)
+	}
+}
+
+func TestSocketTransport(t *testing.T) {
+	dir, err := os.MkdirTemp("", "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var chs []chan [2]any
+	var buses []*Bus
+	for range 3 {
+		transport, err := NewSocketTransport(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bus := NewBus(Options{Transport: transport})
+		buses = append(buses, bus)
+		chs = append(chs, receiveOne(bus))
+	}
+	// A stopped process removes its socket and no longer receives events.
+	buses[2].Stop()
+
+	buses[0].Emit(context.Background(), "transport.user", transportPayload{Name: "bob", Age: 42})
+	expectReceived(t, chs[0])
+	expectReceived(t, chs[1])
+
+	buses[1].Emit(context.Background(), "transport.user", transportPayload{Name: "bob", Age: 42})
+	expectReceived(t, chs[0])
+	expectReceived(t, chs[1])
+
+	buses[0].Stop()
+	buses[1].Stop()
+	if socks, _ := filepath.Glob(filepath.Join(dir, "*.sock")); len(socks) != 0 {
+		t.Errorf("expected sockets to be removed got %v", socks)
+	}
+}
+
+func TestSocketTransportStalledPeer(t *testing.T) {
+	dir := t.TempDir()
+	transport, err := NewSocketTransport(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A peer that accepts connections but never reads from them.
+	stalled, err := net.Listen("unix", filepath.Join(dir, "stalled.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stalled.Close()
+	go func() {
+		for {
+			conn, err := stalled.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	env := Envelope{Topic: "big", Payload: make([]byte, 4<<20)}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := transport.Publish(ctx, env); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected Publish to give up with its ctx, took %v", elapsed)
+	}
+
+	// Close doesn't wait for writes that are still blocked.
+	go transport.Publish(context.Background(), env)
+	time.Sleep(50 * time.Millisecond)
+	closed := make(chan error)
+	go func() { closed <- transport.Close() }()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close blocked on a stalled peer")
+	}
+	if err := transport.Publish(context.Background(), env); !errors.Is(err, ErrTransportClosed) {
+		t.Errorf("expected ErrTransportClosed got %v", err)
+	}
+}
+
+func TestSocketTransportPublishWhileClosing(t *testing.T) {
+	dir := t.TempDir()
+	for range 20 {
+		transport, err := NewSocketTransport(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					err := transport.Publish(context.Background(), Envelope{Topic: "foo"})
+					if errors.Is(err, ErrTransportClosed) {
+						return
+					}
+				}
+			}()
+		}
+		// Drain the envelopes delivered to this process.
+		go func() {
+			for range transport.Receive() {
+			}
+		}()
+		time.Sleep(time.Millisecond)
+		transport.Close()
+		wg.Wait()
+	}
+}
+
+func TestBusWithTransportStopped(t *testing.T) {
+	bus := NewBus(Options{Transport: NewMemoryTransport(1)})
+	bus.Stop()
+	if err := bus.Emit(context.Background(), "foo", 1); !errors.Is(err, ErrStopped) {
+		t.Errorf("expected ErrStopped got %v", err)
+	}
+}
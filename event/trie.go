@@ -0,0 +1,102 @@
+package event
+
+import (
+	"slices"
+	"strings"
+)
+
+// Topics are dot separated, like auth.signup. Subscriptions may use the
+// following wildcards as a whole segment:
+//
+//	auth.*  matches exactly one segment: auth.signup, but not auth.resend.verification
+//	auth.>  matches one or more segments: auth.signup and auth.resend.verification
+//
+// A > is only valid as the last segment of a pattern.
+const (
+	wildcardOne  = "*"
+	wildcardTail = ">"
+)
+
+// node is a node in the subscription trie, keyed by topic segment.
+type node struct {
+	children map[string]*node
+	subs     []Subscription
+}
+
+func newNode() *node {
+	return &node{children: make(map[string]*node)}
+}
+
+func splitTopic(topic string) []string {
+	return strings.Split(topic, ".")
+}
+
+func (n *node) insert(sub Subscription) {
+	for _, seg := range splitTopic(sub.Topic) {
+		child, ok := n.children[seg]
+		if !ok {
+			child = newNode()
+			n.children[seg] = child
+		}
+		n = child
+	}
+	n.subs = append(n.subs, sub)
+}
+
+// remove deletes the subscription and prunes nodes that became empty.
+// It returns true if n itself is empty afterwards.
+func (n *node) remove(sub Subscription, segs []string) bool {
+	if len(segs) == 0 {
+		n.subs = slices.DeleteFunc(n.subs, func(s Subscription) bool {
+			return s.CreatedAt == sub.CreatedAt
+		})
+	} else if child, ok := n.children[segs[0]]; ok {
+		if child.remove(sub, segs[1:]) {
+			delete(n.children, segs[0])
+		}
+	}
+	return len(n.subs) == 0 && len(n.children) == 0
+}
+
+// find returns the node of the given pattern or nil.
+func (n *node) find(pattern string) *node {
+	for _, seg := range splitTopic(pattern) {
+		n = n.children[seg]
+		if n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// match appends all subscriptions whose pattern matches the topic segments.
+func (n *node) match(segs []string, subs []Subscription) []Subscription {
+	if len(segs) == 0 {
+		return append(subs, n.subs...)
+	}
+	if tail, ok := n.children[wildcardTail]; ok {
+		subs = append(subs, tail.subs...)
+	}
+	// A topic segment that is a wildcard itself would otherwise reach
+	// the same child twice.
+	if seg := segs[0]; seg != wildcardOne && seg != wildcardTail {
+		if child, ok := n.children[seg]; ok {
+			subs = child.match(segs[1:], subs)
+		}
+	}
+	if child, ok := n.children[wildcardOne]; ok {
+		subs = child.match(segs[1:], subs)
+	}
+	return subs
+}
+
+// walk calls fn with the pattern and subscriptions of every node
+// that has subscribers.
+func (n *node) walk(prefix []string, fn func(pattern string, subs []Subscription)) {
+	if len(n.subs) > 0 {
+		fn(strings.Join(prefix, "."), n.subs)
+	}
+	for seg, child := range n.children {
+		child.walk(append(prefix, seg), fn)
+	}
+}
@@ -4,7 +4,10 @@ go 1.22.0
 
 require (
 	github.com/a-h/templ v0.2.731
+	github.com/go-chi/chi/v5 v5.0.14
 	github.com/gorilla/sessions v1.3.0
+	github.com/joho/godotenv v1.5.1
+	github.com/mattn/go-sqlite3 v1.14.22
 	github.com/stretchr/testify v1.9.0
 )
 
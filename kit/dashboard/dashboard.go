@@ -0,0 +1,156 @@
+package dashboard
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/anthdm/superkit/event"
+	"github.com/anthdm/superkit/kit"
+	"github.com/go-chi/chi/v5"
+)
+
+// Path is the path the dashboard is mounted on.
+const Path = "/_superkit"
+
+// Config holds the configuration of the development dashboard.
+type Config struct {
+	// DB is used to read the migration status. Optional.
+	DB *sql.DB
+	// MigrationDir is the directory holding the goose migrations.
+	// Defaults to the MIGRATION_DIR environment variable.
+	MigrationDir string
+	// EnvFile is the file the environment variables are loaded from.
+	// Defaults to .env.
+	EnvFile string
+}
+
+// Mount mounts the development dashboard on the given router under
+// /_superkit. Mount is a no-op when the application is not running
+// in development.
+//
+// To see the recent requests in the dashboard, the Recorder middleware
+// needs to be registered on the same router.
+func Mount(router chi.Router, config Config) {
+	if !kit.IsDevelopment() {
+		return
+	}
+	if len(config.MigrationDir) == 0 {
+		config.MigrationDir = kit.Getenv("MIGRATION_DIR", "app/db/migrations")
+	}
+	if len(config.EnvFile) == 0 {
+		config.EnvFile = ".env"
+	}
+	// Keep the recent events around to show them, unless the application
+	// configured the history itself.
+	if !event.Default().HistoryEnabled() {
+		event.KeepHistory(recentEvents, 0)
+	}
+	routes := &routeTable{router: router}
+	router.Get(Path, func(w http.ResponseWriter, r *http.Request) {
+		data, err := collect(routes, config)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := dashboardTemplate.Execute(w, data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+type pageData struct {
+	Env        string
+	Routes     []Route
+	Topics     []Topic
+	Events     []Event
+	Requests   []Request
+	Migrations []Migration
+	Variables  []Variable
+	Errors     []string
+	Now        time.Time
+}
+
+// Topic represents an event topic and its number of subscribers.
+type Topic struct {
+	Name        string
+	Subscribers int
+}
+
+// recentEvents is the number of events shown, and kept per topic when
+// the dashboard enables the history.
+const recentEvents = 50
+
+// Event represents a recently emitted event.
+type Event struct {
+	Time    time.Time
+	Topic   string
+	Payload string
+	// Subscribers is the number of subscriptions currently matching
+	// the topic.
+	Subscribers int
+}
+
+func collectEvents() []Event {
+	history := event.History(">")
+	if len(history) > recentEvents {
+		history = history[len(history)-recentEvents:]
+	}
+	events := make([]Event, len(history))
+	for i, e := range history {
+		payload := fmt.Sprintf("%+v", e.Payload)
+		if utf8.RuneCountInString(payload) > 200 {
+			payload = string([]rune(payload)[:200]) + "..."
+		}
+		// Newest first.
+		events[len(history)-1-i] = Event{
+			Time:        e.EmittedAt,
+			Topic:       e.Topic,
+			Payload:     payload,
+			Subscribers: event.Subscribers(e.Topic),
+		}
+	}
+	return events
+}
+
+func collect(table *routeTable, config Config) (pageData, error) {
+	data := pageData{
+		Env:      kit.Env(),
+		Requests: recorder.requests(),
+		Now:      time.Now(),
+	}
+
+	routes, err := table.get()
+	if err != nil {
+		return data, err
+	}
+	data.Routes = routes
+
+	for name, count := range event.Topics() {
+		data.Topics = append(data.Topics, Topic{Name: name, Subscribers: count})
+	}
+	sort.Slice(data.Topics, func(i, j int) bool {
+		return data.Topics[i].Name < data.Topics[j].Name
+	})
+	data.Events = collectEvents()
+
+	// Migrations and environment are best effort, we rather show what we
+	// have than failing the whole page.
+	migrations, err := collectMigrations(config.DB, config.MigrationDir)
+	if err != nil {
+		data.Errors = append(data.Errors, err.Error())
+	}
+	data.Migrations = migrations
+
+	variables, err := collectVariables(config.EnvFile)
+	if err != nil {
+		data.Errors = append(data.Errors, err.Error())
+	}
+	data.Variables = variables
+
+	return data, nil
+}
@@ -0,0 +1,129 @@
+package dashboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anthdm/superkit/event"
+	"github.com/anthdm/superkit/event/eventtest"
+	"github.com/anthdm/superkit/kit"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectRoutes(t *testing.T) {
+	config := kit.AuthenticationConfig{
+		AuthFunc: func(*kit.Kit) (kit.Auth, error) {
+			return kit.DefaultAuth{}, nil
+		},
+		RedirectURL: "/login",
+	}
+	router := chi.NewMux()
+	router.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+	router.Group(func(r chi.Router) {
+		r.Use(kit.WithAuthentication(config, true))
+		r.Get("/profile", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	routes, err := collectRoutes(router)
+	assert.Nil(t, err)
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "/", routes[0].Pattern)
+	assert.Empty(t, routes[0].Auth)
+	assert.Equal(t, "/profile", routes[1].Pattern)
+	assert.Equal(t, "strict", routes[1].Auth)
+	assert.Equal(t, []string{"kit.WithAuthentication"}, routes[1].Middleware)
+}
+
+func TestRouteTableCollectsOnce(t *testing.T) {
+	var constructed int
+	counting := func(next http.Handler) http.Handler {
+		constructed++
+		return next
+	}
+	router := chi.NewMux()
+	router.With(counting).Get("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	table := &routeTable{router: router}
+	for range 3 {
+		routes, err := table.get()
+		assert.Nil(t, err)
+		assert.Len(t, routes, 1)
+	}
+	// chi builds the handler of the route once, the table once more.
+	assert.Equal(t, 2, constructed)
+}
+
+func TestCollectMigrations(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"20240610163918_add_sessions_table.sql", "20240610161057_create_users_table.sql", "README.md"} {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+	migrations, err := collectMigrations(nil, dir)
+	assert.Nil(t, err)
+	assert.Len(t, migrations, 2)
+	assert.Equal(t, int64(20240610161057), migrations[0].Version)
+	assert.Equal(t, "create_users_table", migrations[0].Name)
+	assert.Equal(t, "unknown", migrations[0].Status)
+}
+
+func TestCollectVariablesMasksSecrets(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	assert.Nil(t, os.WriteFile(envFile, []byte("DB_NAME=app_db\nSUPERKIT_SECRET=supersecret\n"), 0644))
+	t.Setenv("DB_NAME", "other_db")
+
+	variables, err := collectVariables(envFile)
+	assert.Nil(t, err)
+	values := map[string]string{}
+	for _, v := range variables {
+		values[v.Name] = v.Value
+	}
+	assert.Equal(t, "other_db", values["DB_NAME"])
+	assert.Equal(t, "********", values["SUPERKIT_SECRET"])
+}
+
+func TestRecorder(t *testing.T) {
+	t.Setenv("SUPERKIT_ENV", "development")
+	router := chi.NewMux()
+	router.Use(Recorder)
+	router.Get("/users/{id}", kit.Handler(func(kit *kit.Kit) error {
+		return http.ErrNoCookie
+	}))
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	reqs := recorder.requests()
+	assert.NotEmpty(t, reqs)
+	assert.Equal(t, "/users/{id}", reqs[0].Pattern)
+	assert.Equal(t, http.StatusInternalServerError, reqs[0].Status)
+	assert.Equal(t, http.ErrNoCookie.Error(), reqs[0].Error)
+}
+
+func TestCollectEvents(t *testing.T) {
+	rec := eventtest.NewRecorder(t)
+	rec.Bus().KeepHistory(10, 0)
+	event.Emit("dashboard.test", map[string]int{"id": 1})
+	event.Emit("dashboard.other", 2)
+
+	events := collectEvents()
+	assert.Len(t, events, 2)
+	assert.Equal(t, "dashboard.other", events[0].Topic)
+	assert.Equal(t, "map[id:1]", events[1].Payload)
+	// The recorder itself subscribes to all topics.
+	assert.Equal(t, 1, events[1].Subscribers)
+}
+
+func TestCollectEventsTruncatesByRune(t *testing.T) {
+	rec := eventtest.NewRecorder(t)
+	rec.Bus().KeepHistory(10, 0)
+	event.Emit("dashboard.long", strings.Repeat("é", 300))
+
+	events := collectEvents()
+	assert.Len(t, events, 1)
+	assert.Equal(t, strings.Repeat("é", 200)+"...", events[0].Payload)
+}
@@ -0,0 +1,66 @@
+package dashboard
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Variable represents an environment variable with its effective value.
+type Variable struct {
+	Name   string
+	Value  string
+	Masked bool
+}
+
+// maskedValue replaces the values of secret variables, its fixed length
+// doesn't give away the length of the secret.
+const maskedValue = "********"
+
+var secretHints = []string{"SECRET", "PASSWORD", "PASS", "TOKEN", "KEY", "CREDENTIAL", "PRIVATE"}
+
+// collectVariables returns the variables declared in the given env file
+// with their effective value, which might be overwritten by the process
+// environment. Values of secret variables are masked.
+func collectVariables(envFile string) ([]Variable, error) {
+	declared, err := godotenv.Read(envFile)
+	if err != nil {
+		declared = map[string]string{}
+	}
+	// Always show the SUPERKIT variables, even when they are only set in
+	// the process environment.
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		if strings.HasPrefix(name, "SUPERKIT_") {
+			declared[name] = ""
+		}
+	}
+	variables := make([]Variable, 0, len(declared))
+	for name, value := range declared {
+		if env, ok := os.LookupEnv(name); ok {
+			value = env
+		}
+		v := Variable{Name: name, Value: value}
+		if isSecret(name) && len(value) > 0 {
+			v.Value = maskedValue
+			v.Masked = true
+		}
+		variables = append(variables, v)
+	}
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Name < variables[j].Name
+	})
+	return variables, err
+}
+
+func isSecret(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, hint := range secretHints {
+		if strings.Contains(upper, hint) {
+			return true
+		}
+	}
+	return false
+}
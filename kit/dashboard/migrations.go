@@ -0,0 +1,90 @@
+package dashboard
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migration represents a goose migration file and its status.
+type Migration struct {
+	Version int64
+	Name    string
+	// Status is either "applied", "pending" or "unknown" when no
+	// database is configured.
+	Status    string
+	AppliedAt time.Time
+}
+
+// collectMigrations reads the goose migration files from dir and, when db is
+// not nil, their status from the goose_db_version table.
+func collectMigrations(db *sql.DB, dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	var migrations []Migration
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			continue
+		}
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    name,
+			Status:  "unknown",
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	if db == nil {
+		return migrations, nil
+	}
+
+	rows, err := db.Query("SELECT version_id, is_applied, tstamp FROM goose_db_version ORDER BY id")
+	if err != nil {
+		return migrations, fmt.Errorf("failed to read migration status: %w", err)
+	}
+	defer rows.Close()
+
+	// goose appends a row for every up and down migration, so the last row
+	// of a version holds its current state.
+	type status struct {
+		applied bool
+		at      time.Time
+	}
+	statuses := map[int64]status{}
+	for rows.Next() {
+		var (
+			version int64
+			applied bool
+			at      sql.NullTime
+		)
+		if err := rows.Scan(&version, &applied, &at); err != nil {
+			return migrations, fmt.Errorf("failed to read migration status: %w", err)
+		}
+		statuses[version] = status{applied: applied, at: at.Time}
+	}
+	for i, m := range migrations {
+		migrations[i].Status = "pending"
+		if s, ok := statuses[m.Version]; ok && s.applied {
+			migrations[i].Status = "applied"
+			migrations[i].AppliedAt = s.at
+		}
+	}
+	return migrations, rows.Err()
+}
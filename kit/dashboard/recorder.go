@@ -0,0 +1,94 @@
+package dashboard
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anthdm/superkit/kit"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// maxRequests is the number of recent requests kept by the Recorder.
+const maxRequests = 100
+
+// Request represents a recorded request.
+type Request struct {
+	Time     time.Time
+	Method   string
+	Path     string
+	Pattern  string
+	Status   int
+	Duration time.Duration
+	Error    string
+}
+
+var recorder = &requestRecorder{}
+
+// Recorder is a middleware that records the most recent requests, their
+// timings and errors so they can be inspected in the dashboard. Requests
+// to the dashboard itself are not recorded. Recorder is a no-op when the
+// application is not running in development.
+func Recorder(next http.Handler) http.Handler {
+	if !kit.IsDevelopment() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		var (
+			start = time.Now()
+			ww    = middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			errs  []string
+		)
+		ctx := kit.WithErrorReporter(r.Context(), func(err error) {
+			errs = append(errs, err.Error())
+		})
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		req := Request{
+			Time:     start,
+			Method:   r.Method,
+			Path:     r.URL.Path,
+			Status:   ww.Status(),
+			Duration: time.Since(start),
+			Error:    strings.Join(errs, "; "),
+		}
+		if req.Status == 0 {
+			req.Status = http.StatusOK
+		}
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			req.Pattern = rctx.RoutePattern()
+		}
+		recorder.record(req)
+	})
+}
+
+type requestRecorder struct {
+	mu   sync.RWMutex
+	reqs []Request
+}
+
+func (r *requestRecorder) record(req Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reqs = append(r.reqs, req)
+	if len(r.reqs) > maxRequests {
+		r.reqs = r.reqs[len(r.reqs)-maxRequests:]
+	}
+}
+
+// requests returns the recorded requests, most recent first.
+func (r *requestRecorder) requests() []Request {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	reqs := make([]Request, len(r.reqs))
+	for i, req := range r.reqs {
+		reqs[len(r.reqs)-1-i] = req
+	}
+	return reqs
+}
@@ -0,0 +1,102 @@
+package dashboard
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Route represents a single route registered on the router.
+type Route struct {
+	Method     string
+	Pattern    string
+	Handler    string
+	Middleware []string
+	// Auth is empty when the route has no authentication middleware,
+	// "strict" when unauthenticated requests are redirected and
+	// "optional" otherwise.
+	Auth string
+}
+
+var closureSuffixRegex = regexp.MustCompile(`(\.func\d+|\.\d+|-fm)+$`)
+
+type authStricter interface {
+	AuthStrict() bool
+}
+
+// routeTable collects the routes of the router once, on first use, as
+// routes may be registered after Mount. Collecting calls the middleware
+// constructors, which should not happen on every request.
+type routeTable struct {
+	router chi.Routes
+	once   sync.Once
+	routes []Route
+	err    error
+}
+
+func (t *routeTable) get() ([]Route, error) {
+	t.once.Do(func() {
+		t.routes, t.err = collectRoutes(t.router)
+	})
+	return t.routes, t.err
+}
+
+func collectRoutes(router chi.Routes) ([]Route, error) {
+	var routes []Route
+	err := chi.Walk(router, func(method, pattern string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		route := Route{
+			Method:  method,
+			Pattern: pattern,
+			Handler: funcName(handler),
+		}
+		for _, mw := range middlewares {
+			route.Middleware = append(route.Middleware, funcName(mw))
+			// The auth middleware of kit returns a handler that exposes its
+			// strictness, hence we wrap a noop handler to inspect it.
+			if h, ok := mw(http.NotFoundHandler()).(authStricter); ok {
+				route.Auth = "optional"
+				if h.AuthStrict() {
+					route.Auth = "strict"
+				}
+			}
+		}
+		routes = append(routes, route)
+		return nil
+	})
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Pattern == routes[j].Pattern {
+			return routes[i].Method < routes[j].Method
+		}
+		return routes[i].Pattern < routes[j].Pattern
+	})
+	return routes, err
+}
+
+// funcName returns a readable name for the given function or handler.
+//
+//	github.com/anthdm/superkit/kit.WithAuthentication.func1 => kit.WithAuthentication
+func funcName(v any) string {
+	if v == nil {
+		return ""
+	}
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Func {
+		return reflect.TypeOf(v).String()
+	}
+	fn := runtime.FuncForPC(val.Pointer())
+	if fn == nil {
+		return val.Type().String()
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	// Strip the suffixes the compiler adds to closures and method values.
+	return closureSuffixRegex.ReplaceAllString(name, "")
+}
@@ -0,0 +1,131 @@
+package dashboard
+
+import (
+	"html/template"
+	"strings"
+	"time"
+)
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"join": strings.Join,
+	"ms": func(d time.Duration) string {
+		return d.Round(time.Microsecond).String()
+	},
+	"clock": func(t time.Time) string {
+		return t.Format("15:04:05")
+	},
+}).Parse(dashboardHTML))
+
+const dashboardHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8"/>
+	<meta name="viewport" content="width=device-width, initial-scale=1.0"/>
+	<title>superkit dashboard</title>
+	<style>
+		body { font-family: ui-sans-serif, system-ui, sans-serif; margin: 0; padding: 2rem; background: #0a0a0a; color: #e5e5e5; font-size: 14px; }
+		h1 { font-size: 1.5rem; margin: 0 0 .25rem 0; }
+		h2 { font-size: 1.1rem; margin: 2.5rem 0 .75rem 0; }
+		nav a { color: #a3a3a3; margin-right: 1rem; }
+		table { width: 100%; border-collapse: collapse; }
+		th, td { text-align: left; padding: .4rem .6rem; border-bottom: 1px solid #262626; vertical-align: top; }
+		th { color: #a3a3a3; font-weight: 500; }
+		code { font-family: ui-monospace, monospace; font-size: 13px; }
+		.muted { color: #737373; }
+		.ok { color: #4ade80; }
+		.warn { color: #facc15; }
+		.err { color: #f87171; }
+	</style>
+</head>
+<body>
+	<h1>superkit</h1>
+	<div class="muted">environment: {{ .Env }} &middot; {{ clock .Now }}</div>
+	<nav>
+		<a href="#routes">routes</a>
+		<a href="#events">events</a>
+		<a href="#recent-events">recent events</a>
+		<a href="#requests">requests</a>
+		<a href="#migrations">migrations</a>
+		<a href="#environment">environment</a>
+	</nav>
+	{{ range .Errors }}<p class="err">{{ . }}</p>{{ end }}
+
+	<h2 id="routes">Routes ({{ len .Routes }})</h2>
+	<table>
+		<tr><th>Method</th><th>Pattern</th><th>Auth</th><th>Middleware</th><th>Handler</th></tr>
+		{{ range .Routes }}
+		<tr>
+			<td><code>{{ .Method }}</code></td>
+			<td><code>{{ .Pattern }}</code></td>
+			<td>{{ if eq .Auth "strict" }}<span class="warn">strict</span>{{ else if eq .Auth "optional" }}<span class="ok">optional</span>{{ else }}<span class="muted">none</span>{{ end }}</td>
+			<td><code class="muted">{{ join .Middleware ", " }}</code></td>
+			<td><code class="muted">{{ .Handler }}</code></td>
+		</tr>
+		{{ end }}
+	</table>
+
+	<h2 id="events">Event topics ({{ len .Topics }})</h2>
+	<table>
+		<tr><th>Topic</th><th>Subscribers</th></tr>
+		{{ range .Topics }}
+		<tr><td><code>{{ .Name }}</code></td><td>{{ .Subscribers }}</td></tr>
+		{{ else }}
+		<tr><td colspan="2" class="muted">no subscribers</td></tr>
+		{{ end }}
+	</table>
+
+	<h2 id="recent-events">Recent events ({{ len .Events }})</h2>
+	<table>
+		<tr><th>Time</th><th>Topic</th><th>Subscribers</th><th>Payload</th></tr>
+		{{ range .Events }}
+		<tr>
+			<td class="muted">{{ clock .Time }}</td>
+			<td><code>{{ .Topic }}</code></td>
+			<td class="{{ if eq .Subscribers 0 }}warn{{ end }}">{{ .Subscribers }}</td>
+			<td><code class="muted">{{ .Payload }}</code></td>
+		</tr>
+		{{ else }}
+		<tr><td colspan="4" class="muted">no events emitted yet</td></tr>
+		{{ end }}
+	</table>
+
+	<h2 id="requests">Recent requests ({{ len .Requests }})</h2>
+	<table>
+		<tr><th>Time</th><th>Method</th><th>Path</th><th>Route</th><th>Status</th><th>Duration</th><th>Error</th></tr>
+		{{ range .Requests }}
+		<tr>
+			<td class="muted">{{ clock .Time }}</td>
+			<td><code>{{ .Method }}</code></td>
+			<td><code>{{ .Path }}</code></td>
+			<td><code class="muted">{{ .Pattern }}</code></td>
+			<td class="{{ if ge .Status 500 }}err{{ else if ge .Status 400 }}warn{{ else }}ok{{ end }}">{{ .Status }}</td>
+			<td>{{ ms .Duration }}</td>
+			<td class="err">{{ .Error }}</td>
+		</tr>
+		{{ else }}
+		<tr><td colspan="7" class="muted">no requests recorded yet, make sure the dashboard.Recorder middleware is registered</td></tr>
+		{{ end }}
+	</table>
+
+	<h2 id="migrations">Migrations ({{ len .Migrations }})</h2>
+	<table>
+		<tr><th>Version</th><th>Name</th><th>Status</th></tr>
+		{{ range .Migrations }}
+		<tr>
+			<td><code>{{ .Version }}</code></td>
+			<td>{{ .Name }}</td>
+			<td>{{ if eq .Status "applied" }}<span class="ok">applied</span> <span class="muted">{{ .AppliedAt.Format "2006-01-02 15:04:05" }}</span>{{ else if eq .Status "pending" }}<span class="warn">pending</span>{{ else }}<span class="muted">unknown</span>{{ end }}</td>
+		</tr>
+		{{ end }}
+	</table>
+
+	<h2 id="environment">Environment</h2>
+	<table>
+		<tr><th>Name</th><th>Value</th></tr>
+		{{ range .Variables }}
+		<tr><td><code>{{ .Name }}</code></td><td><code{{ if .Masked }} class="muted"{{ end }}>{{ .Value }}</code></td></tr>
+		{{ end }}
+	</table>
+</body>
+</html>
+`
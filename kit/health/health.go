@@ -0,0 +1,142 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CheckFunc is a readiness check. A nil error means the check passed.
+type CheckFunc func(ctx context.Context) error
+
+// Timeout is the maximum duration all readiness checks together may take.
+var Timeout = 5 * time.Second
+
+var (
+	mu     sync.RWMutex
+	checks = map[string]CheckFunc{}
+)
+
+// Register registers a readiness check under the given name.
+// Registering a check with an existing name replaces the check.
+func Register(name string, check CheckFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+	checks[name] = check
+}
+
+// Unregister removes the readiness check with the given name.
+func Unregister(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(checks, name)
+}
+
+// PingDB returns a CheckFunc that pings the given database, for example
+// the *sql.DB returned by db.NewSQL.
+//
+//	health.Register("db", health.PingDB(sqlDB))
+func PingDB(db *sql.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// Result holds the outcome of a single readiness check.
+type Result struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Healthz is the liveness handler. It responds with 200 as long as the
+// process is able to serve requests.
+func Healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// Readyz is the readiness handler. It runs all registered checks
+// concurrently and responds with 200 if all of them passed, otherwise
+// with 503. The results are written as JSON.
+func Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), Timeout)
+	defer cancel()
+
+	results, ok := Check(ctx)
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"ok":     ok,
+		"checks": results,
+	})
+}
+
+// Check runs all the registered checks concurrently and returns their
+// results sorted by name, and true if all of them passed.
+func Check(ctx context.Context) ([]Result, bool) {
+	mu.RLock()
+	registered := make(map[string]CheckFunc, len(checks))
+	for name, check := range checks {
+		registered[name] = check
+	}
+	mu.RUnlock()
+
+	var (
+		wg      sync.WaitGroup
+		resmu   sync.Mutex
+		results = make([]Result, 0, len(registered))
+		ok      = true
+	)
+	for name, check := range registered {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+			start := time.Now()
+			err := runCheck(ctx, check)
+			result := Result{
+				Name:     name,
+				OK:       err == nil,
+				Duration: time.Since(start).String(),
+			}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			resmu.Lock()
+			defer resmu.Unlock()
+			results = append(results, result)
+			ok = ok && result.OK
+		}(name, check)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results, ok
+}
+
+// runCheck runs the check, making sure a check that ignores the context
+// cannot block the readiness handler past its deadline.
+func runCheck(ctx context.Context, check CheckFunc) error {
+	errch := make(chan error, 1)
+	go func() {
+		errch <- check(ctx)
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errch:
+		return err
+	}
+}
@@ -0,0 +1,51 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadyz(t *testing.T) {
+	Register("cache", func(ctx context.Context) error { return nil })
+	defer Unregister("cache")
+
+	rec := httptest.NewRecorder()
+	Readyz(rec, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	Register("queue", func(ctx context.Context) error { return errors.New("queue unreachable") })
+	defer Unregister("queue")
+
+	rec = httptest.NewRecorder()
+	Readyz(rec, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var body struct {
+		OK     bool     `json:"ok"`
+		Checks []Result `json:"checks"`
+	}
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.False(t, body.OK)
+	assert.Len(t, body.Checks, 2)
+	assert.Equal(t, "queue", body.Checks[1].Name)
+	assert.Equal(t, "queue unreachable", body.Checks[1].Error)
+}
+
+func TestReadyzTimeout(t *testing.T) {
+	Register("slow", func(ctx context.Context) error {
+		select {}
+	})
+	defer Unregister("slow")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, ok := Check(ctx)
+	assert.False(t, ok)
+	assert.Equal(t, context.Canceled.Error(), results[0].Error)
+}
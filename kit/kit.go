@@ -10,6 +10,9 @@ import (
 	"os"
 
 	"github.com/a-h/templ"
+	"github.com/anthdm/superkit/event"
+	"github.com/anthdm/superkit/kit/tenant"
+	"github.com/anthdm/superkit/kit/trace"
 	"github.com/gorilla/sessions"
 	"github.com/joho/godotenv"
 )
@@ -22,6 +25,8 @@ type ErrorHandlerFunc func(kit *Kit, err error)
 
 type AuthKey struct{}
 
+type errorReporterKey struct{}
+
 type Auth interface {
 	Check() bool
 }
@@ -52,9 +57,19 @@ func (kit *Kit) Auth() Auth {
 	return value
 }
 
+// Tenant returns the current tenant or nil if the request has none.
+// See the kit/tenant package.
+func (kit *Kit) Tenant() *tenant.Tenant {
+	return tenant.FromContext(kit.Request.Context())
+}
+
 // GetSession return a session by its name. GetSession always
-// returns a session even if it does not exist.
+// returns a session even if it does not exist. When the request
+// has a tenant, the session is scoped to that tenant.
 func (kit *Kit) GetSession(name string) *sessions.Session {
+	if t := kit.Tenant(); t != nil {
+		name = t.ID + "_" + name
+	}
 	sess, _ := store.Get(kit.Request, name)
 	return sess
 }
@@ -94,8 +109,14 @@ func (kit *Kit) Bytes(status int, b []byte) error {
 	return err
 }
 
+// Render renders the given templ component. When tracing is enabled
+// the rendering is recorded as a span.
 func (kit *Kit) Render(c templ.Component) error {
-	return c.Render(kit.Request.Context(), kit.Response)
+	ctx, span := trace.Start(kit.Request.Context(), "render")
+	defer span.End()
+	err := c.Render(ctx, kit.Response)
+	span.RecordError(err)
+	return err
 }
 
 func (kit *Kit) Getenv(name string, def string) string {
@@ -109,6 +130,7 @@ func Handler(h HandlerFunc) http.HandlerFunc {
 			Request:  r,
 		}
 		if err := h(kit); err != nil {
+			reportError(r.Context(), err)
 			if errorHandler != nil {
 				errorHandler(kit, err)
 				return
@@ -125,23 +147,62 @@ type AuthenticationConfig struct {
 
 func WithAuthentication(config AuthenticationConfig, strict bool) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			kit := &Kit{
-				Response: w,
-				Request:  r,
-			}
-			auth, err := config.AuthFunc(kit)
-			if err != nil {
-				errorHandler(kit, err)
-				return
-			}
-			if strict && !auth.Check() && r.URL.Path != config.RedirectURL {
-				kit.Redirect(http.StatusSeeOther, config.RedirectURL)
-				return
-			}
-			ctx := context.WithValue(r.Context(), AuthKey{}, auth)
-			next.ServeHTTP(w, r.WithContext(ctx))
-		})
+		return authenticationHandler{
+			config: config,
+			strict: strict,
+			next:   next,
+		}
+	}
+}
+
+// authenticationHandler is the http.Handler returned by the WithAuthentication
+// middleware. It is a named type so tools (like the development dashboard)
+// can inspect the strictness of the middleware.
+type authenticationHandler struct {
+	config AuthenticationConfig
+	strict bool
+	next   http.Handler
+}
+
+// AuthStrict returns true if unauthenticated requests are redirected.
+func (h authenticationHandler) AuthStrict() bool { return h.strict }
+
+func (h authenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	kit := &Kit{
+		Response: w,
+		Request:  r,
+	}
+	auth, err := h.config.AuthFunc(kit)
+	if err != nil {
+		reportError(r.Context(), err)
+		errorHandler(kit, err)
+		return
+	}
+	if h.strict && !auth.Check() && r.URL.Path != h.config.RedirectURL {
+		kit.Redirect(http.StatusSeeOther, h.config.RedirectURL)
+		return
+	}
+	ctx := context.WithValue(r.Context(), AuthKey{}, auth)
+	h.next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// WithErrorReporter returns a copy of ctx in which every error returned
+// by a HandlerFunc is reported to fn, before the error handler is called.
+// Reporters registered on a parent context are still called.
+func WithErrorReporter(ctx context.Context, fn func(error)) context.Context {
+	if parent, ok := ctx.Value(errorReporterKey{}).(func(error)); ok {
+		report := fn
+		fn = func(err error) {
+			parent(err)
+			report(err)
+		}
+	}
+	return context.WithValue(ctx, errorReporterKey{}, fn)
+}
+
+func reportError(ctx context.Context, err error) {
+	if report, ok := ctx.Value(errorReporterKey{}).(func(error)); ok {
+		report(err)
 	}
 }
 
@@ -181,3 +242,8 @@ func Setup() {
 	}
 	store = sessions.NewCookieStore([]byte(appSecret))
 }
+
+func init() {
+	// Event handlers can tell on whose behalf an event was emitted.
+	event.PropagateValues(AuthKey{})
+}
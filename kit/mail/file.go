@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer is a Mailer that writes every message as an .eml file
+// into a maildir structured directory (tmp, new, cur). The files can
+// be opened with any mail client, which is handy during development.
+type FileMailer struct {
+	dir string
+}
+
+// NewFileMailer returns a new FileMailer writing into the given directory.
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{
+		dir: dir,
+	}
+}
+
+// Send writes the message to the "new" folder of the maildir.
+func (m *FileMailer) Send(ctx context.Context, msg *Message) error {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(m.dir, sub), 0755); err != nil {
+			return err
+		}
+	}
+	b, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	// Following the maildir convention the message is first written into
+	// tmp and then moved into new, so readers never see partial messages.
+	name := fmt.Sprintf("%d.%s.eml", time.Now().UnixNano(), randomID()[:8])
+	tmpPath := filepath.Join(m.dir, "tmp", name)
+	if err := os.WriteFile(tmpPath, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filepath.Join(m.dir, "new", name))
+}
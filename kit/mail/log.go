@@ -0,0 +1,31 @@
+package mail
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// LogMailer is a Mailer that logs messages instead of sending them.
+// It is the default Mailer and is meant to be used during development.
+// Only the recipients and subject are logged, as bodies hold secrets
+// like verification links, use the FileMailer to read them.
+type LogMailer struct {
+	logger *slog.Logger
+}
+
+// NewLogMailer returns a new LogMailer that logs with the default slog logger.
+func NewLogMailer() *LogMailer {
+	return &LogMailer{
+		logger: slog.Default(),
+	}
+}
+
+// Send logs the recipients and subject of the message.
+func (m *LogMailer) Send(ctx context.Context, msg *Message) error {
+	m.logger.InfoContext(ctx, "mail sent",
+		"to", strings.Join(msg.Recipients(), ", "),
+		"subject", msg.Subject,
+	)
+	return nil
+}
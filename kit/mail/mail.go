@@ -0,0 +1,173 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/a-h/templ"
+	"github.com/anthdm/superkit/kit"
+)
+
+const (
+	DriverSMTP = "smtp"
+	DriverLog  = "log"
+	DriverFile = "file"
+)
+
+// Mailer is the interface that wraps the Send method.
+// Every mail driver (SMTP, log, file, ...) implements Mailer.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+var (
+	mailermu sync.RWMutex
+	mailer   Mailer = NewLogMailer()
+)
+
+// Use sets the Mailer used by the package level Send function.
+func Use(m Mailer) {
+	mailermu.Lock()
+	defer mailermu.Unlock()
+	mailer = m
+}
+
+// Default returns the Mailer used by the package level Send function.
+func Default() Mailer {
+	mailermu.RLock()
+	defer mailermu.RUnlock()
+	return mailer
+}
+
+// Send sends the given message using the default Mailer.
+func Send(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		msg.From = os.Getenv("MAIL_FROM")
+	}
+	return Default().Send(ctx, msg)
+}
+
+// Attachment represents a file attached to a message. Inline
+// attachments are referenced from the HTML body by their ContentID.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	ContentID   string
+	Inline      bool
+	Data        []byte
+}
+
+// Message represents a single email message.
+type Message struct {
+	From        string
+	To          []string
+	Cc          []string
+	Bcc         []string
+	ReplyTo     string
+	Subject     string
+	Text        string
+	HTML        string
+	Headers     map[string]string
+	Attachments []Attachment
+}
+
+// NewMessage returns a new Message for the given recipient and subject.
+func NewMessage(to string, subject string) *Message {
+	return &Message{
+		To:      []string{to},
+		Subject: subject,
+	}
+}
+
+// Render renders the given templ component as the HTML body of the
+// message. If the message has no plain text body yet, a plain text
+// alternative is derived from the rendered HTML.
+func (m *Message) Render(ctx context.Context, c templ.Component) error {
+	var buf bytes.Buffer
+	if err := c.Render(ctx, &buf); err != nil {
+		return fmt.Errorf("failed to render mail component: %w", err)
+	}
+	m.HTML = buf.String()
+	if len(m.Text) == 0 {
+		m.Text = HTMLToText(m.HTML)
+	}
+	return nil
+}
+
+// Attach adds a regular file attachment to the message.
+func (m *Message) Attach(filename string, contentType string, data []byte) {
+	m.Attachments = append(m.Attachments, Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		Data:        data,
+	})
+}
+
+// Embed adds an inline attachment (an image for example) to the message
+// and returns the "cid:" URL that can be used in the HTML body.
+//
+//	<img src={ msg.Embed("logo.png", "image/png", logo) }/>
+func (m *Message) Embed(filename string, contentType string, data []byte) string {
+	cid := fmt.Sprintf("%s@superkit", filename)
+	m.Attachments = append(m.Attachments, Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		ContentID:   cid,
+		Inline:      true,
+		Data:        data,
+	})
+	return "cid:" + cid
+}
+
+// Recipients returns all the recipients of the message, including
+// the Cc and Bcc addresses.
+func (m *Message) Recipients() []string {
+	rcpts := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	rcpts = append(rcpts, m.To...)
+	rcpts = append(rcpts, m.Cc...)
+	rcpts = append(rcpts, m.Bcc...)
+	return rcpts
+}
+
+// FromEnv creates a Mailer based on the MAIL_* environment variables.
+//
+//	MAIL_DRIVER   = smtp | log | file (defaults to log, required in production)
+//	MAIL_HOST     = smtp host
+//	MAIL_PORT     = smtp port (defaults to 587)
+//	MAIL_USERNAME = smtp username
+//	MAIL_PASSWORD = smtp password
+//	MAIL_DIR      = directory used by the file driver (defaults to tmp/mail)
+func FromEnv() (Mailer, error) {
+	driver := os.Getenv("MAIL_DRIVER")
+	if len(driver) == 0 {
+		// Falling back to the log driver in production would silently
+		// drop every email.
+		if kit.IsProduction() {
+			return nil, fmt.Errorf("MAIL_DRIVER is not set")
+		}
+		driver = DriverLog
+	}
+	switch driver {
+	case DriverSMTP:
+		port, err := strconv.Atoi(kit.Getenv("MAIL_PORT", "587"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAIL_PORT: %w", err)
+		}
+		return NewSMTPMailer(SMTPConfig{
+			Host:     os.Getenv("MAIL_HOST"),
+			Port:     port,
+			Username: os.Getenv("MAIL_USERNAME"),
+			Password: os.Getenv("MAIL_PASSWORD"),
+		}), nil
+	case DriverLog:
+		return NewLogMailer(), nil
+	case DriverFile:
+		return NewFileMailer(kit.Getenv("MAIL_DIR", "tmp/mail")), nil
+	default:
+		return nil, fmt.Errorf("invalid mail driver (%s)", driver)
+	}
+}
@@ -0,0 +1,170 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/stretchr/testify/assert"
+)
+
+func verifyEmail(link string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<html><head><title>x</title></head><body>`+
+			`<h1>Welcome</h1><p>Please verify your email &amp; login.</p>`+
+			`<a href="`+link+`">Verify</a></body></html>`)
+		return err
+	})
+}
+
+func TestHTMLToText(t *testing.T) {
+	text := HTMLToText(`<h1>Welcome</h1><p>Please verify your email &amp; login.</p><a href="http://foo.com/verify">Verify</a><ul><li>one</li><li>two</li></ul>`)
+	assert.Equal(t, "Welcome\n\nPlease verify your email & login.\n\nVerify (http://foo.com/verify)\n\n- one\n- two", text)
+}
+
+func TestMessageRender(t *testing.T) {
+	msg := NewMessage("foo@bar.com", "Verify your email")
+	assert.Nil(t, msg.Render(context.Background(), verifyEmail("http://foo.com/verify")))
+	assert.Contains(t, msg.HTML, "<h1>Welcome</h1>")
+	assert.Equal(t, "Welcome\n\nPlease verify your email & login.\n\nVerify (http://foo.com/verify)", msg.Text)
+}
+
+func TestMessageBytes(t *testing.T) {
+	msg := NewMessage("foo@bar.com", "Verify your email")
+	msg.From = "noreply@superkit.dev"
+	assert.Nil(t, msg.Render(context.Background(), verifyEmail("http://foo.com/verify")))
+	cid := msg.Embed("logo.png", "image/png", []byte("png"))
+	assert.Equal(t, "cid:logo.png@superkit", cid)
+	msg.Attach("invoice.pdf", "application/pdf", []byte("pdf"))
+
+	b, err := msg.Bytes()
+	assert.Nil(t, err)
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(b))
+	assert.Nil(t, err)
+	assert.Equal(t, "foo@bar.com", parsed.Header.Get("To"))
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+	related, err := mr.NextPart()
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(related.Header.Get("Content-Type"), "multipart/related"))
+
+	attachment, err := mr.NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, "invoice.pdf", attachment.FileName())
+
+	_, err = mr.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder()
+	assert.Nil(t, rec.Last())
+
+	msg := NewMessage("foo@bar.com", "hello")
+	assert.Nil(t, rec.Send(context.Background(), msg))
+	assert.Len(t, rec.Messages(), 1)
+	assert.Equal(t, msg, rec.Last())
+
+	rec.Reset()
+	assert.Empty(t, rec.Messages())
+}
+
+func TestUseConcurrently(t *testing.T) {
+	prev := Default()
+	t.Cleanup(func() { Use(prev) })
+	rec := NewRecorder()
+	Use(rec)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			Use(rec)
+		}()
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, Send(context.Background(), NewMessage("foo@bar.com", "hello")))
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, rec.Messages(), 10)
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("MAIL_DRIVER", "")
+	mailer, err := FromEnv()
+	assert.Nil(t, err)
+	assert.IsType(t, &LogMailer{}, mailer)
+
+	t.Setenv("SUPERKIT_ENV", "production")
+	_, err = FromEnv()
+	assert.NotNil(t, err)
+
+	t.Setenv("MAIL_DRIVER", DriverFile)
+	mailer, err = FromEnv()
+	assert.Nil(t, err)
+	assert.IsType(t, &FileMailer{}, mailer)
+}
+
+func TestLogMailerOmitsBody(t *testing.T) {
+	var buf bytes.Buffer
+	m := &LogMailer{logger: slog.New(slog.NewTextHandler(&buf, nil))}
+	msg := NewMessage("foo@bar.com", "Verify your email address")
+	msg.Text = "https://example.com/email/verify?token=secret"
+	assert.Nil(t, m.Send(context.Background(), msg))
+	assert.Contains(t, buf.String(), "foo@bar.com")
+	assert.Contains(t, buf.String(), "Verify your email address")
+	assert.NotContains(t, buf.String(), "secret")
+}
+
+func TestMessageBytesRejectsHeaderInjection(t *testing.T) {
+	msgs := []*Message{
+		{To: []string{"foo@bar.com\r\nBcc: evil@bar.com"}},
+		{Cc: []string{"foo@bar.com\nBcc: evil@bar.com"}},
+		{ReplyTo: "foo@bar.com\rBcc: evil@bar.com"},
+		{Headers: map[string]string{"X-Campaign": "a\r\nBcc: evil@bar.com"}},
+		{Headers: map[string]string{"X-Evil\r\nBcc": "evil@bar.com"}},
+		{Attachments: []Attachment{{Filename: "a.txt", ContentType: "text/plain\r\nX-Evil: 1"}}},
+	}
+	for _, msg := range msgs {
+		msg.From = "app@bar.com"
+		_, err := msg.Bytes()
+		assert.NotNil(t, err, "%+v", msg)
+	}
+
+	msg := NewMessage("foo@bar.com", "subject\r\nBcc: evil@bar.com")
+	b, err := msg.Bytes()
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "\r\nBcc:")
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := t.TempDir()
+	m := NewFileMailer(dir)
+	msg := NewMessage("foo@bar.com", "hello")
+	msg.Text = "hello world"
+	assert.Nil(t, m.Send(context.Background(), msg))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "new"))
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+
+	b, err := os.ReadFile(filepath.Join(dir, "new", entries[0].Name()))
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "hello world")
+}
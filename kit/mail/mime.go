@@ -0,0 +1,247 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bytes encodes the message as a RFC 5322 message with MIME parts.
+//
+// The structure of the message depends on its content:
+//
+//	multipart/mixed
+//	├── multipart/related
+//	│   ├── multipart/alternative
+//	│   │   ├── text/plain
+//	│   │   └── text/html
+//	│   └── inline attachments
+//	└── attachments
+func (m *Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", m.From)
+	if len(m.To) > 0 {
+		header.Set("To", strings.Join(m.To, ", "))
+	}
+	if len(m.Cc) > 0 {
+		header.Set("Cc", strings.Join(m.Cc, ", "))
+	}
+	if len(m.ReplyTo) > 0 {
+		header.Set("Reply-To", m.ReplyTo)
+	}
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-ID", fmt.Sprintf("<%s@superkit>", randomID()))
+	header.Set("MIME-Version", "1.0")
+	for key, value := range m.Headers {
+		header.Set(key, value)
+	}
+
+	var inline, attachments []Attachment
+	for _, a := range m.Attachments {
+		if a.Inline {
+			inline = append(inline, a)
+		} else {
+			attachments = append(attachments, a)
+		}
+	}
+
+	var body bytes.Buffer
+	contentType, err := m.writeMixed(&body, attachments, inline)
+	if err != nil {
+		return nil, err
+	}
+	header.Set("Content-Type", contentType)
+	if !strings.HasPrefix(contentType, "multipart/") {
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+	}
+
+	if err := writeHeader(&buf, header); err != nil {
+		return nil, err
+	}
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+func (m *Message) writeMixed(w io.Writer, attachments, inline []Attachment) (string, error) {
+	if len(attachments) == 0 {
+		return m.writeRelated(w, inline)
+	}
+	mw := multipart.NewWriter(w)
+	var part bytes.Buffer
+	contentType, err := m.writeRelated(&part, inline)
+	if err != nil {
+		return "", err
+	}
+	if err := writePart(mw, contentType, part.Bytes()); err != nil {
+		return "", err
+	}
+	for _, a := range attachments {
+		if err := writeAttachment(mw, a); err != nil {
+			return "", err
+		}
+	}
+	return "multipart/mixed; boundary=" + mw.Boundary(), mw.Close()
+}
+
+func (m *Message) writeRelated(w io.Writer, inline []Attachment) (string, error) {
+	if len(inline) == 0 {
+		return m.writeAlternative(w)
+	}
+	mw := multipart.NewWriter(w)
+	var part bytes.Buffer
+	contentType, err := m.writeAlternative(&part)
+	if err != nil {
+		return "", err
+	}
+	if err := writePart(mw, contentType, part.Bytes()); err != nil {
+		return "", err
+	}
+	for _, a := range inline {
+		if err := writeAttachment(mw, a); err != nil {
+			return "", err
+		}
+	}
+	return "multipart/related; boundary=" + mw.Boundary(), mw.Close()
+}
+
+func (m *Message) writeAlternative(w io.Writer) (string, error) {
+	const (
+		textContentType = "text/plain; charset=utf-8"
+		htmlContentType = "text/html; charset=utf-8"
+	)
+	if len(m.HTML) == 0 {
+		return textContentType, writeQuotedPrintable(w, m.Text)
+	}
+	mw := multipart.NewWriter(w)
+	for _, alt := range []struct{ contentType, body string }{
+		{textContentType, m.Text},
+		{htmlContentType, m.HTML},
+	} {
+		if len(alt.body) == 0 {
+			continue
+		}
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {alt.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return "", err
+		}
+		if err := writeQuotedPrintable(pw, alt.body); err != nil {
+			return "", err
+		}
+	}
+	return "multipart/alternative; boundary=" + mw.Boundary(), mw.Close()
+}
+
+func writePart(mw *multipart.Writer, contentType string, body []byte) error {
+	header := textproto.MIMEHeader{"Content-Type": {contentType}}
+	if !strings.HasPrefix(contentType, "multipart/") {
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+	}
+	if err := checkHeader(header); err != nil {
+		return err
+	}
+	pw, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	_, err = pw.Write(body)
+	return err
+}
+
+func writeAttachment(mw *multipart.Writer, a Attachment) error {
+	contentType := a.ContentType
+	if len(contentType) == 0 {
+		contentType = "application/octet-stream"
+	}
+	disposition := "attachment"
+	if a.Inline {
+		disposition = "inline"
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "base64")
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.Filename}))
+	if len(a.ContentID) > 0 {
+		header.Set("Content-ID", "<"+a.ContentID+">")
+	}
+	if err := checkHeader(header); err != nil {
+		return err
+	}
+	pw, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	// RFC 2045 limits encoded lines to 76 characters.
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(pw, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = io.WriteString(pw, encoded+"\r\n")
+	return err
+}
+
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qw := quotedprintable.NewWriter(w)
+	if _, err := io.WriteString(qw, s); err != nil {
+		return err
+	}
+	return qw.Close()
+}
+
+func writeHeader(w io.Writer, header textproto.MIMEHeader) error {
+	if err := checkHeader(header); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(w, "%s: %s\r\n", key, value)
+		}
+	}
+	io.WriteString(w, "\r\n")
+	return nil
+}
+
+// checkHeader rejects line breaks in the header, which would let values
+// like a recipient address inject headers of their own.
+func checkHeader(header textproto.MIMEHeader) error {
+	for key, values := range header {
+		if strings.ContainsAny(key, "\r\n:") {
+			return fmt.Errorf("invalid mail header name %q", key)
+		}
+		for _, value := range values {
+			if strings.ContainsAny(value, "\r\n") {
+				return fmt.Errorf("invalid mail header %s: contains a line break", key)
+			}
+		}
+	}
+	return nil
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
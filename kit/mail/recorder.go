@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// Recorder is a Mailer that keeps all sent messages in memory.
+// It is meant to be used in tests.
+//
+//	rec := mail.NewRecorder()
+//	mail.Use(rec)
+//	...
+//	msg := rec.Last()
+type Recorder struct {
+	mu       sync.RWMutex
+	messages []*Message
+}
+
+// NewRecorder returns a new Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Send records the message.
+func (r *Recorder) Send(ctx context.Context, msg *Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, msg)
+	return nil
+}
+
+// Messages returns all the recorded messages.
+func (r *Recorder) Messages() []*Message {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	messages := make([]*Message, len(r.messages))
+	copy(messages, r.messages)
+	return messages
+}
+
+// Last returns the last recorded message or nil if there is none.
+func (r *Recorder) Last() *Message {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.messages) == 0 {
+		return nil
+	}
+	return r.messages[len(r.messages)-1]
+}
+
+// Reset removes all the recorded messages.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = nil
+}
@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+// SMTPConfig holds the configuration of the SMTP driver.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// SMTPMailer is a Mailer that delivers messages to an SMTP server.
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+// NewSMTPMailer returns a new SMTPMailer for the given configuration.
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	return &SMTPMailer{
+		config: config,
+	}
+}
+
+// Send sends the message to the configured SMTP server.
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	if len(msg.From) == 0 {
+		return errors.New("mail: message has no sender")
+	}
+	rcpts := msg.Recipients()
+	if len(rcpts) == 0 {
+		return errors.New("mail: message has no recipients")
+	}
+	b, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if len(m.config.Username) > 0 {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+
+	// net/smtp has no context support, so we run the delivery in a separate
+	// goroutine to give the caller the possibility to stop waiting on it.
+	errch := make(chan error, 1)
+	go func() {
+		errch <- smtp.SendMail(addr, auth, msg.From, rcpts, b)
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errch:
+		if err != nil {
+			return fmt.Errorf("mail: failed to send message via %s: %w", addr, err)
+		}
+		return nil
+	}
+}
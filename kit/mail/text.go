@@ -0,0 +1,78 @@
+package mail
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	tagRegex        = regexp.MustCompile(`(?is)<(/?)([a-z0-9]+)([^>]*)>`)
+	hrefRegex       = regexp.MustCompile(`(?is)href\s*=\s*["']([^"']*)["']`)
+	skipBlockRegex  = regexp.MustCompile(`(?is)<(head|style|script|title)[^>]*>.*?</(head|style|script|title)>`)
+	commentRegex    = regexp.MustCompile(`(?s)<!--.*?-->`)
+	spaceRegex      = regexp.MustCompile(`[ \t\r\f\v]+`)
+	blankLinesRegex = regexp.MustCompile(`\n{3,}`)
+)
+
+var blockTags = map[string]bool{
+	"p": true, "div": true, "table": true, "tr": true, "ul": true, "ol": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"section": true, "header": true, "footer": true, "blockquote": true,
+}
+
+// HTMLToText converts the given HTML into a readable plain text
+// version. It is used to derive the plain text alternative of messages
+// rendered from templ components. Links are written as "text (url)".
+func HTMLToText(s string) string {
+	s = commentRegex.ReplaceAllString(s, "")
+	s = skipBlockRegex.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "\n", " ")
+
+	var (
+		b     strings.Builder
+		hrefs []string
+		last  int
+	)
+	for _, m := range tagRegex.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(s[last:m[0]])
+		last = m[1]
+
+		closing := s[m[2]:m[3]] == "/"
+		tag := strings.ToLower(s[m[4]:m[5]])
+		attrs := s[m[6]:m[7]]
+
+		switch {
+		case tag == "br":
+			b.WriteString("\n")
+		case tag == "li" && !closing:
+			b.WriteString("\n- ")
+		case tag == "td" && closing:
+			b.WriteString(" ")
+		case tag == "a" && !closing:
+			href := ""
+			if match := hrefRegex.FindStringSubmatch(attrs); match != nil {
+				href = match[1]
+			}
+			hrefs = append(hrefs, href)
+		case tag == "a" && closing && len(hrefs) > 0:
+			href := hrefs[len(hrefs)-1]
+			hrefs = hrefs[:len(hrefs)-1]
+			if len(href) > 0 && !strings.HasPrefix(href, "#") {
+				b.WriteString(" (" + href + ")")
+			}
+		case blockTags[tag]:
+			b.WriteString("\n\n")
+		}
+	}
+	b.WriteString(s[last:])
+
+	text := html.UnescapeString(b.String())
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spaceRegex.ReplaceAllString(line, " "))
+	}
+	text = strings.Join(lines, "\n")
+	text = blankLinesRegex.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type label struct {
+	name  string
+	value string
+}
+
+type counterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func newCounterVec(name, help string, labels ...string) *counterVec {
+	return &counterVec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		values: map[string]float64{},
+	}
+}
+
+func (c *counterVec) inc(values ...string) {
+	key := labelKey(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key]++
+}
+
+func (c *counterVec) write(ew *expositionWriter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ew.header(c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.values) {
+		ew.sample(c.name, makeLabels(c.labels, key), c.values[key])
+	}
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+type histogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	values map[string]*histogram
+}
+
+func newHistogramVec(name, help string, buckets []float64, labels ...string) *histogramVec {
+	return &histogramVec{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: buckets,
+		values:  map[string]*histogram{},
+	}
+}
+
+func (h *histogramVec) observe(v float64, values ...string) {
+	key := labelKey(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hist, ok := h.values[key]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = hist
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += v
+}
+
+func (h *histogramVec) write(ew *expositionWriter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ew.header(h.name, h.help, "histogram")
+	for _, key := range sortedKeys(h.values) {
+		hist := h.values[key]
+		labels := makeLabels(h.labels, key)
+		for i, upper := range h.buckets {
+			ew.sample(h.name+"_bucket", append(labels, label{"le", formatFloat(upper)}), float64(hist.counts[i]))
+		}
+		ew.sample(h.name+"_bucket", append(labels, label{"le", "+Inf"}), float64(hist.count))
+		ew.sample(h.name+"_sum", labels, hist.sum)
+		ew.sample(h.name+"_count", labels, float64(hist.count))
+	}
+}
+
+// expositionWriter writes metrics in the Prometheus text format and
+// remembers the first write error.
+type expositionWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *expositionWriter) printf(format string, args ...any) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
+
+func (ew *expositionWriter) header(name, help, kind string) {
+	ew.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (ew *expositionWriter) sample(name string, labels []label, value float64) {
+	if len(labels) == 0 {
+		ew.printf("%s %s\n", name, formatFloat(value))
+		return
+	}
+	pairs := make([]string, len(labels))
+	for i, l := range labels {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, l.name, labelEscaper.Replace(l.value))
+	}
+	ew.printf("%s{%s} %s\n", name, strings.Join(pairs, ","), formatFloat(value))
+}
+
+func (ew *expositionWriter) gauge(name, help string, labels []label, value float64) {
+	ew.header(name, help, "gauge")
+	ew.sample(name, labels, value)
+}
+
+// labelKey joins label values with a separator that can't be part of
+// valid UTF-8 input.
+const labelSep = "\xff"
+
+func labelKey(values []string) string {
+	return strings.Join(values, labelSep)
+}
+
+func makeLabels(names []string, key string) []label {
+	values := strings.Split(key, labelSep)
+	labels := make([]label, len(names))
+	for i, name := range names {
+		labels[i] = label{name, values[i]}
+	}
+	return labels
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
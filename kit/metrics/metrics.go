@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anthdm/superkit/event"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// DefaultBuckets are the default latency buckets in seconds, the same
+// as the default buckets of the Prometheus client libraries.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+var (
+	requestsTotal = newCounterVec(
+		"http_requests_total",
+		"Total number of HTTP requests by method, route pattern and status code.",
+		"method", "route", "status",
+	)
+	requestDuration = newHistogramVec(
+		"http_request_duration_seconds",
+		"HTTP request latencies in seconds by method and route pattern.",
+		DefaultBuckets,
+		"method", "route",
+	)
+	eventHandlerDuration = newHistogramVec(
+		"event_handler_duration_seconds",
+		"Event handler durations in seconds by topic.",
+		DefaultBuckets,
+		"topic",
+	)
+	requestsInFlight atomic.Int64
+
+	dbmu sync.RWMutex
+	dbs  = map[string]*sql.DB{}
+)
+
+func init() {
+	event.AddHooks(event.Hooks{
+		OnHandled: func(topic string, d time.Duration, _ error) {
+			eventHandlerDuration.observe(d.Seconds(), topic)
+		},
+	})
+}
+
+// RegisterDB registers a database, for example the *sql.DB returned by
+// db.NewSQL, so its sql.DBStats are exposed under the given name.
+func RegisterDB(name string, db *sql.DB) {
+	dbmu.Lock()
+	defer dbmu.Unlock()
+	dbs[name] = db
+}
+
+// Middleware records the request count, latency and in-flight requests.
+// Requests are labeled by their chi route pattern (/users/{id}) instead
+// of their path, to keep the number of series bounded.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestsInFlight.Add(1)
+		defer requestsInFlight.Add(-1)
+
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); len(pattern) > 0 {
+				route = pattern
+			}
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		requestsTotal.inc(r.Method, route, strconv.Itoa(status))
+		requestDuration.observe(time.Since(start).Seconds(), r.Method, route)
+	})
+}
+
+// Handler returns a http.Handler that serves all metrics in the
+// Prometheus text exposition format.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Write(w)
+	})
+}
+
+// Write writes all metrics in the Prometheus text exposition format to w.
+func Write(w io.Writer) error {
+	ew := &expositionWriter{w: w}
+	requestsTotal.write(ew)
+	requestDuration.write(ew)
+	ew.gauge("http_requests_in_flight", "Number of HTTP requests currently being served.", nil, float64(requestsInFlight.Load()))
+	ew.gauge("event_queue_depth", "Number of emitted events waiting to be dispatched.", nil, float64(event.QueueDepth()))
+	eventHandlerDuration.write(ew)
+	writeEventStats(ew)
+	writeDBStats(ew)
+	return ew.err
+}
+
+func writeEventStats(ew *expositionWriter) {
+	stats := event.Stats()
+	if len(stats) == 0 {
+		return
+	}
+	topics := sortedKeys(stats)
+	metrics := []struct {
+		name  string
+		help  string
+		kind  string
+		value func(event.TopicStats) float64
+	}{
+		{"event_emitted_total", "Total number of emitted events by topic.", "counter",
+			func(s event.TopicStats) float64 { return float64(s.Emitted) }},
+		{"event_handled_total", "Total number of successful handler attempts by topic.", "counter",
+			func(s event.TopicStats) float64 { return float64(s.Handled) }},
+		{"event_failed_total", "Total number of failed handler attempts by topic.", "counter",
+			func(s event.TopicStats) float64 { return float64(s.Failed) }},
+		{"event_dead_lettered_total", "Total number of dead lettered events by topic.", "counter",
+			func(s event.TopicStats) float64 { return float64(s.DeadLettered) }},
+		{"event_dropped_total", "Total number of events dropped by topic because the queue was full.", "counter",
+			func(s event.TopicStats) float64 { return float64(s.Dropped) }},
+		{"event_topic_queue_depth", "Number of events and deliveries waiting to be handled by topic.", "gauge",
+			func(s event.TopicStats) float64 { return float64(s.QueueDepth) }},
+	}
+	for _, m := range metrics {
+		ew.header(m.name, m.help, m.kind)
+		for _, topic := range topics {
+			ew.sample(m.name, []label{{"topic", topic}}, m.value(stats[topic]))
+		}
+	}
+}
+
+func writeDBStats(ew *expositionWriter) {
+	dbmu.RLock()
+	names := make([]string, 0, len(dbs))
+	for name := range dbs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	stats := make([]sql.DBStats, len(names))
+	for i, name := range names {
+		stats[i] = dbs[name].Stats()
+	}
+	dbmu.RUnlock()
+
+	if len(names) == 0 {
+		return
+	}
+	metrics := []struct {
+		name  string
+		help  string
+		kind  string
+		value func(sql.DBStats) float64
+	}{
+		{"db_max_open_connections", "Maximum number of open connections to the database.", "gauge",
+			func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }},
+		{"db_open_connections", "Number of established connections, both in use and idle.", "gauge",
+			func(s sql.DBStats) float64 { return float64(s.OpenConnections) }},
+		{"db_in_use_connections", "Number of connections currently in use.", "gauge",
+			func(s sql.DBStats) float64 { return float64(s.InUse) }},
+		{"db_idle_connections", "Number of idle connections.", "gauge",
+			func(s sql.DBStats) float64 { return float64(s.Idle) }},
+		{"db_wait_count_total", "Total number of connections waited for.", "counter",
+			func(s sql.DBStats) float64 { return float64(s.WaitCount) }},
+		{"db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", "counter",
+			func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }},
+		{"db_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.", "counter",
+			func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }},
+		{"db_max_idle_time_closed_total", "Total number of connections closed due to SetConnMaxIdleTime.", "counter",
+			func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) }},
+		{"db_max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.", "counter",
+			func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }},
+	}
+	for _, m := range metrics {
+		ew.header(m.name, m.help, m.kind)
+		for i, name := range names {
+			ew.sample(m.name, []label{{"db", name}}, m.value(stats[i]))
+		}
+	}
+}
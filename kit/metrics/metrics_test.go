@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anthdm/superkit/event"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	router := chi.NewMux()
+	router.Use(Middleware)
+	router.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	router.Handle("/metrics", Handler())
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/2", nil))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/users/{id}",status="418"} 2`)
+	assert.Contains(t, body, `http_request_duration_seconds_bucket{method="GET",route="/users/{id}",le="+Inf"} 2`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/users/{id}"} 2`)
+	assert.Contains(t, body, "# TYPE http_requests_in_flight gauge\nhttp_requests_in_flight 1\n")
+	assert.Contains(t, body, "# TYPE event_queue_depth gauge\n")
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	h := newHistogramVec("test_seconds", "test", []float64{0.1, 1}, "topic")
+	h.observe(0.05, "a")
+	h.observe(0.5, "a")
+	h.observe(5, "a")
+
+	var b strings.Builder
+	ew := &expositionWriter{w: &b}
+	h.write(ew)
+	assert.Nil(t, ew.err)
+	assert.Equal(t, `# HELP test_seconds test
+# TYPE test_seconds histogram
+test_seconds_bucket{topic="a",le="0.1"} 1
+test_seconds_bucket{topic="a",le="1"} 2
+test_seconds_bucket{topic="a",le="+Inf"} 3
+test_seconds_sum{topic="a"} 5.55
+test_seconds_count{topic="a"} 3
+`, b.String())
+}
+
+func TestEventMetrics(t *testing.T) {
+	sub := event.Subscribe("metrics.test", func(context.Context, any) error { return nil })
+	defer event.Unsubscribe(sub)
+	assert.Nil(t, event.EmitSync(context.Background(), "metrics.test", 1))
+
+	var b strings.Builder
+	assert.Nil(t, Write(&b))
+	body := b.String()
+	assert.Contains(t, body, `event_emitted_total{topic="metrics.test"} 1`)
+	assert.Contains(t, body, `event_handled_total{topic="metrics.test"} 1`)
+	assert.Contains(t, body, `event_handler_duration_seconds_count{topic="metrics.test"} 1`)
+}
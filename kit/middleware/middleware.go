@@ -3,6 +3,12 @@ package middleware
 import (
 	"context"
 	"net/http"
+
+	"github.com/anthdm/superkit/event"
+	"github.com/anthdm/superkit/kit"
+	"github.com/anthdm/superkit/kit/trace"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 type (
@@ -16,3 +22,51 @@ func WithRequest(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// WithTracing starts a server span for every request, continuing the
+// trace of an incoming W3C traceparent header if present. The span is
+// named after the route pattern and records the status code and the
+// error returned by the kit handler. WithTracing is a no-op when no
+// trace exporter is configured.
+func WithTracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !trace.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := r.Context()
+		if sc, err := trace.ParseTraceparent(r.Header.Get("traceparent")); err == nil {
+			ctx = trace.ContextWithRemote(ctx, sc)
+		}
+		ctx, span := trace.Start(ctx, r.Method+" "+r.URL.Path,
+			trace.WithKind(trace.KindServer),
+			trace.WithAttributes(
+				"http.method", r.Method,
+				"http.target", r.URL.Path,
+				"http.user_agent", r.UserAgent(),
+			),
+		)
+		defer span.End()
+
+		ctx = kit.WithErrorReporter(ctx, span.RecordError)
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes("http.status_code", status)
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); len(pattern) > 0 {
+				span.SetName(r.Method + " " + pattern)
+				span.SetAttributes("http.route", pattern)
+			}
+		}
+	})
+}
+
+func init() {
+	// Carry the request ID of chi's RequestID middleware to event handlers.
+	event.PropagateValues(middleware.RequestIDKey)
+}
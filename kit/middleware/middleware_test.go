@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anthdm/superkit/kit"
+	"github.com/anthdm/superkit/kit/trace"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingExporter struct {
+	spans []*trace.Span
+}
+
+func (e *recordingExporter) Export(span *trace.Span) error {
+	e.spans = append(e.spans, span)
+	return nil
+}
+
+func TestWithTracing(t *testing.T) {
+	exp := &recordingExporter{}
+	trace.UseExporter(exp)
+	defer trace.UseExporter(nil)
+
+	router := chi.NewMux()
+	router.Use(WithTracing)
+	router.Get("/users/{id}", kit.Handler(func(kit *kit.Kit) error {
+		return errors.New("user not found")
+	}))
+
+	req := httptest.NewRequest("GET", "/users/1?token=secret", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Len(t, exp.spans, 1)
+	span := exp.spans[0]
+	assert.Equal(t, "GET /users/{id}", span.Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.Context.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", span.ParentID.String())
+	assert.Equal(t, "/users/1", span.Attributes["http.target"])
+	assert.Equal(t, http.StatusInternalServerError, span.Attributes["http.status_code"])
+	assert.EqualError(t, span.Err, "user not found")
+}
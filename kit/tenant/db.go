@@ -0,0 +1,138 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/anthdm/superkit/db"
+)
+
+// Mode is the way tenant data is isolated in the database.
+type Mode int
+
+const (
+	// SharedSchema stores the data of all tenants in the same tables,
+	// scoped by a tenant_id column.
+	SharedSchema Mode = iota
+	// DatabasePerTenant stores the data of every tenant in its own database.
+	DatabasePerTenant
+)
+
+// Column is the name of the column holding the tenant ID in the
+// shared schema mode.
+var Column = "tenant_id"
+
+// ErrNoTenant is returned when a tenant scoped operation is called
+// with a context that has no tenant.
+var ErrNoTenant = errors.New("no tenant in context")
+
+// DB returns the tenant scoped database for a request.
+//
+//	tenants := tenant.NewDB(tenant.DatabasePerTenant, db.Config{
+//		Driver: db.DriverSqlite3,
+//		Name:   "app_{tenant}.db",
+//	})
+//	sqlDB, err := tenants.Get(ctx)
+type DB struct {
+	mode   Mode
+	config db.Config
+	shared *sql.DB
+
+	mu  sync.Mutex
+	dbs map[string]*sql.DB
+}
+
+// NewDB returns a new DB for the given mode. In the shared schema mode
+// a single database is opened with the given config. In the database per
+// tenant mode the config is used as a template for every tenant database:
+// the Name is replaced by the Database of the tenant or, if empty, every
+// {tenant} placeholder in the Name is replaced by the tenant ID. A Name
+// without the placeholder is rejected, as every tenant would share it.
+func NewDB(mode Mode, config db.Config) (*DB, error) {
+	if mode == DatabasePerTenant && !strings.Contains(config.Name, "{tenant}") {
+		return nil, fmt.Errorf("database name %q has no {tenant} placeholder", config.Name)
+	}
+	d := &DB{
+		mode:   mode,
+		config: config,
+		dbs:    map[string]*sql.DB{},
+	}
+	if mode == SharedSchema {
+		shared, err := db.NewSQL(config)
+		if err != nil {
+			return nil, err
+		}
+		d.shared = shared
+	}
+	return d, nil
+}
+
+// Mode returns the mode of the DB.
+func (d *DB) Mode() Mode { return d.mode }
+
+// Get returns the database of the tenant of ctx. In the shared schema
+// mode this is the shared database, queries need to be scoped with Where.
+// Tenant databases are opened on first use and kept open.
+func (d *DB) Get(ctx context.Context) (*sql.DB, error) {
+	if d.mode == SharedSchema {
+		return d.shared, nil
+	}
+	t := FromContext(ctx)
+	if t == nil {
+		return nil, ErrNoTenant
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if sqlDB, ok := d.dbs[t.ID]; ok {
+		return sqlDB, nil
+	}
+	sqlDB, err := db.NewSQL(d.configFor(t))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database of tenant %s: %w", t.ID, err)
+	}
+	d.dbs[t.ID] = sqlDB
+	return sqlDB, nil
+}
+
+func (d *DB) configFor(t *Tenant) db.Config {
+	config := d.config
+	if len(t.Database) > 0 {
+		config.Name = t.Database
+	} else {
+		config.Name = strings.ReplaceAll(config.Name, "{tenant}", t.ID)
+	}
+	return config
+}
+
+// Close closes all the opened databases.
+func (d *DB) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var errs []error
+	if d.shared != nil {
+		errs = append(errs, d.shared.Close())
+	}
+	for id, sqlDB := range d.dbs {
+		errs = append(errs, sqlDB.Close())
+		delete(d.dbs, id)
+	}
+	return errors.Join(errs...)
+}
+
+// Where returns the condition and argument that scope a query to the
+// tenant of ctx in the shared schema mode.
+//
+//	cond, arg, err := tenant.Where(ctx)
+//	rows, err := sqlDB.QueryContext(ctx, "SELECT * FROM projects WHERE "+cond, arg)
+func Where(ctx context.Context) (string, any, error) {
+	id := ID(ctx)
+	if len(id) == 0 {
+		return "", nil, ErrNoTenant
+	}
+	return Column + " = ?", id, nil
+}
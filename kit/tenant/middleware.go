@@ -0,0 +1,79 @@
+package tenant
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Config holds the configuration of the tenant middleware.
+type Config struct {
+	Resolver Resolver
+	Store    Store
+	// Optional lets requests without a tenant key through, without a
+	// tenant in their context. Useful for the landing page on the root domain.
+	Optional bool
+	// StripPrefix removes the tenant segment from the request path, to be
+	// used with the FromPathPrefix resolver.
+	StripPrefix bool
+	// NotFoundHandler is called when the tenant could not be resolved or
+	// does not exist. Defaults to http.NotFound.
+	NotFoundHandler http.Handler
+}
+
+// Middleware resolves the tenant of every request and loads it into the
+// request context. Use tenant.FromContext, kit.Tenant() or view.Tenant(ctx)
+// to access it.
+//
+//	router.Use(tenant.Middleware(tenant.Config{
+//		Resolver: tenant.FromSubdomain("example.com"),
+//		Store:    tenant.SQLStore{DB: sqlDB},
+//	}))
+func Middleware(config Config) func(http.Handler) http.Handler {
+	notFound := config.NotFoundHandler
+	if notFound == nil {
+		notFound = http.NotFoundHandler()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := config.Resolver(r)
+			if len(key) == 0 {
+				if config.Optional {
+					next.ServeHTTP(w, r)
+					return
+				}
+				notFound.ServeHTTP(w, r)
+				return
+			}
+			t, err := config.Store.Tenant(r.Context(), key)
+			if err != nil {
+				if !errors.Is(err, ErrNotFound) {
+					slog.Error("failed to load tenant", "err", err, "key", key)
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+				notFound.ServeHTTP(w, r)
+				return
+			}
+			if config.StripPrefix {
+				r = stripPrefix(r, "/"+key)
+			}
+			next.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), t)))
+		})
+	}
+}
+
+func stripPrefix(r *http.Request, prefix string) *http.Request {
+	path := strings.TrimPrefix(r.URL.Path, prefix)
+	if len(path) == len(r.URL.Path) {
+		return r
+	}
+	if len(path) == 0 {
+		path = "/"
+	}
+	r2 := r.Clone(r.Context())
+	r2.URL.Path = path
+	r2.URL.RawPath = ""
+	return r2
+}
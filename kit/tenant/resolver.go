@@ -0,0 +1,72 @@
+package tenant
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Resolver returns the key of the tenant of the request, or an empty
+// string if the request has none.
+type Resolver func(r *http.Request) string
+
+// FromSubdomain resolves the tenant from the subdomain of the request
+// host under the given root domain.
+//
+//	tenant.FromSubdomain("example.com") // acme.example.com => acme
+func FromSubdomain(root string) Resolver {
+	suffix := "." + strings.TrimPrefix(strings.ToLower(root), ".")
+	return func(r *http.Request) string {
+		host := strings.ToLower(r.Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if !strings.HasSuffix(host, suffix) {
+			return ""
+		}
+		sub := strings.TrimSuffix(host, suffix)
+		// Only the first level subdomain identifies the tenant.
+		if i := strings.LastIndex(sub, "."); i >= 0 {
+			sub = sub[i+1:]
+		}
+		if sub == "www" {
+			return ""
+		}
+		return sub
+	}
+}
+
+// FromHeader resolves the tenant from the given request header.
+//
+//	tenant.FromHeader("X-Tenant")
+func FromHeader(name string) Resolver {
+	return func(r *http.Request) string {
+		return strings.TrimSpace(r.Header.Get(name))
+	}
+}
+
+// FromPathPrefix resolves the tenant from the first segment of the
+// request path. Use it together with Config.StripPrefix so the routes
+// don't need to include the tenant segment.
+//
+//	/acme/dashboard => acme
+func FromPathPrefix() Resolver {
+	return func(r *http.Request) string {
+		path := strings.TrimPrefix(r.URL.Path, "/")
+		key, _, _ := strings.Cut(path, "/")
+		return key
+	}
+}
+
+// First returns a Resolver that returns the first tenant key resolved
+// by the given resolvers.
+func First(resolvers ...Resolver) Resolver {
+	return func(r *http.Request) string {
+		for _, resolve := range resolvers {
+			if key := resolve(r); len(key) > 0 {
+				return key
+			}
+		}
+		return ""
+	}
+}
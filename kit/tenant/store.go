@@ -0,0 +1,57 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Store loads tenants by their key.
+type Store interface {
+	Tenant(ctx context.Context, key string) (*Tenant, error)
+}
+
+// StaticStore is a Store backed by a map of tenants by their key.
+type StaticStore map[string]*Tenant
+
+// Tenant returns the tenant for the given key or ErrNotFound.
+func (s StaticStore) Tenant(ctx context.Context, key string) (*Tenant, error) {
+	t, ok := s[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return t, nil
+}
+
+// SQLStore is a Store that loads tenants by their slug from a table
+// with the columns id, slug, name and db_name.
+//
+//	create table tenants(
+//		id text primary key,
+//		slug text unique not null,
+//		name text not null,
+//		db_name text not null default ''
+//	);
+type SQLStore struct {
+	DB    *sql.DB
+	Table string
+}
+
+// Tenant returns the tenant for the given key or ErrNotFound.
+func (s SQLStore) Tenant(ctx context.Context, key string) (*Tenant, error) {
+	table := s.Table
+	if len(table) == 0 {
+		table = "tenants"
+	}
+	query := fmt.Sprintf(`SELECT id, slug, name, db_name FROM %s WHERE slug = ?`, table)
+	var t Tenant
+	err := s.DB.QueryRowContext(ctx, query, key).Scan(&t.ID, &t.Slug, &t.Name, &t.Database)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,61 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/anthdm/superkit/event"
+)
+
+// ErrNotFound is returned by a Store when the tenant does not exist.
+var ErrNotFound = errors.New("tenant not found")
+
+// Tenant represents a customer of a multi tenant application.
+type Tenant struct {
+	ID string
+	// Slug is the value the tenant is resolved by, like its subdomain.
+	Slug string
+	Name string
+	// Database is the name of the tenant database. It is only used
+	// in the database per tenant mode.
+	Database string
+}
+
+// ContextKey is the key the current Tenant is stored under in the
+// request context.
+type ContextKey struct{}
+
+// WithTenant returns a copy of ctx holding the given tenant.
+func WithTenant(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, ContextKey{}, t)
+}
+
+// FromContext returns the tenant of ctx or nil if there is none.
+func FromContext(ctx context.Context) *Tenant {
+	t, _ := ctx.Value(ContextKey{}).(*Tenant)
+	return t
+}
+
+// ID returns the ID of the tenant of ctx or an empty string if there is none.
+func ID(ctx context.Context) string {
+	if t := FromContext(ctx); t != nil {
+		return t.ID
+	}
+	return ""
+}
+
+// CacheKey prefixes the given key with the ID of the tenant of ctx,
+// so cached values never leak between tenants.
+//
+//	tenant.CacheKey(ctx, "users:1") // => acme:users:1
+func CacheKey(ctx context.Context, key string) string {
+	if id := ID(ctx); len(id) > 0 {
+		return id + ":" + key
+	}
+	return key
+}
+
+func init() {
+	// Events emitted while serving a request are handled for its tenant.
+	event.PropagateValues(ContextKey{})
+}
@@ -0,0 +1,105 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anthdm/superkit/db"
+	"github.com/stretchr/testify/assert"
+)
+
+var testStore = StaticStore{
+	"acme": {ID: "1", Slug: "acme", Name: "Acme"},
+}
+
+func TestResolvers(t *testing.T) {
+	sub := FromSubdomain("example.com")
+	for host, expected := range map[string]string{
+		"acme.example.com":      "acme",
+		"acme.example.com:3000": "acme",
+		"eu.acme.example.com":   "acme",
+		"www.example.com":       "",
+		"example.com":           "",
+		"acme.other.com":        "",
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Host = host
+		assert.Equal(t, expected, sub(r), host)
+	}
+
+	r := httptest.NewRequest("GET", "/acme/dashboard", nil)
+	assert.Equal(t, "acme", FromPathPrefix()(r))
+	assert.Equal(t, "", FromHeader("X-Tenant")(r))
+	r.Header.Set("X-Tenant", "other")
+	assert.Equal(t, "other", First(FromHeader("X-Tenant"), FromPathPrefix())(r))
+}
+
+func TestMiddleware(t *testing.T) {
+	var (
+		tenant *Tenant
+		path   string
+	)
+	handler := Middleware(Config{
+		Resolver:    FromPathPrefix(),
+		Store:       testStore,
+		StripPrefix: true,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant = FromContext(r.Context())
+		path = r.URL.Path
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/acme/dashboard", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Acme", tenant.Name)
+	assert.Equal(t, "/dashboard", path)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/unknown/dashboard", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestMiddlewareOptional(t *testing.T) {
+	called := false
+	handler := Middleware(Config{
+		Resolver: FromHeader("X-Tenant"),
+		Store:    testStore,
+		Optional: true,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		assert.Nil(t, FromContext(r.Context()))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.True(t, called)
+}
+
+func TestScoping(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "users:1", CacheKey(ctx, "users:1"))
+	_, _, err := Where(ctx)
+	assert.Equal(t, ErrNoTenant, err)
+
+	ctx = WithTenant(ctx, testStore["acme"])
+	assert.Equal(t, "1:users:1", CacheKey(ctx, "users:1"))
+	cond, arg, err := Where(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "tenant_id = ?", cond)
+	assert.Equal(t, "1", arg)
+}
+
+func TestDatabasePerTenantConfig(t *testing.T) {
+	d, err := NewDB(DatabasePerTenant, db.Config{Driver: db.DriverSqlite3, Name: "app_{tenant}.db"})
+	assert.Nil(t, err)
+	assert.Equal(t, "app_1.db", d.configFor(&Tenant{ID: "1"}).Name)
+	assert.Equal(t, "acme.db", d.configFor(&Tenant{ID: "1", Database: "acme.db"}).Name)
+
+	_, err = d.Get(context.Background())
+	assert.Equal(t, ErrNoTenant, err)
+}
+
+func TestDatabasePerTenantConfigWithoutPlaceholder(t *testing.T) {
+	_, err := NewDB(DatabasePerTenant, db.Config{Driver: db.DriverSqlite3, Name: "app.db"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,22 @@
+package trace
+
+import (
+	"context"
+	"io"
+
+	"github.com/a-h/templ"
+)
+
+// Component wraps the given templ component so its rendering is
+// recorded as a span with the given name.
+//
+//	kit.Render(trace.Component("landing.Index", landing.Index()))
+func Component(name string, c templ.Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		ctx, span := Start(ctx, "render "+name)
+		defer span.End()
+		err := c.Render(ctx, w)
+		span.RecordError(err)
+		return err
+	})
+}
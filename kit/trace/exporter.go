@@ -0,0 +1,192 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ExporterStdout   = "stdout"
+	ExporterOTLPFile = "otlp-file"
+)
+
+// FromEnv creates an Exporter based on the SUPERKIT_TRACE_* environment
+// variables. It returns a nil Exporter if tracing is not configured.
+//
+//	SUPERKIT_TRACE_EXPORTER = stdout | otlp-file
+//	SUPERKIT_TRACE_FILE     = file used by the otlp-file exporter (defaults to tmp/traces.jsonl)
+func FromEnv() (Exporter, error) {
+	switch name := os.Getenv("SUPERKIT_TRACE_EXPORTER"); name {
+	case "":
+		return nil, nil
+	case ExporterStdout:
+		return NewStdoutExporter(os.Stdout), nil
+	case ExporterOTLPFile:
+		path := os.Getenv("SUPERKIT_TRACE_FILE")
+		if len(path) == 0 {
+			path = "tmp/traces.jsonl"
+		}
+		return NewOTLPFileExporter(path)
+	default:
+		return nil, fmt.Errorf("invalid trace exporter (%s)", name)
+	}
+}
+
+// StdoutExporter writes every span as a single human readable line.
+//
+//	trace=4bf9... span=00f0... parent=- POST /signup 412.3ms http.status=200
+type StdoutExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStdoutExporter returns a new StdoutExporter writing to w.
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{w: w}
+}
+
+// Export writes the span to the underlying writer.
+func (e *StdoutExporter) Export(span *Span) error {
+	var b strings.Builder
+	parent := "-"
+	if span.ParentID.IsValid() {
+		parent = span.ParentID.String()
+	}
+	fmt.Fprintf(&b, "trace=%s span=%s parent=%s %s %s",
+		span.Context.TraceID, span.Context.SpanID, parent, span.Name,
+		span.Duration().Round(time.Microsecond))
+	keys := make([]string, 0, len(span.Attributes))
+	for key := range span.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%v", key, span.Attributes[key])
+	}
+	if span.Err != nil {
+		fmt.Fprintf(&b, " error=%q", span.Err.Error())
+	}
+	b.WriteString("\n")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+// OTLPFileExporter writes every span as an OTLP/JSON ExportTraceServiceRequest
+// on a single line, the same format the OpenTelemetry collector file exporter
+// uses. The file can be replayed into any OTLP compatible backend.
+type OTLPFileExporter struct {
+	mu          sync.Mutex
+	file        *os.File
+	serviceName string
+}
+
+// NewOTLPFileExporter returns a new OTLPFileExporter appending to the given file.
+func NewOTLPFileExporter(path string) (*OTLPFileExporter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	serviceName := os.Getenv("SUPERKIT_SERVICE_NAME")
+	if len(serviceName) == 0 {
+		serviceName = "superkit"
+	}
+	return &OTLPFileExporter{
+		file:        file,
+		serviceName: serviceName,
+	}, nil
+}
+
+// Export appends the span to the file.
+func (e *OTLPFileExporter) Export(span *Span) error {
+	b, err := json.Marshal(otlpRequest(e.serviceName, span))
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err = e.file.Write(append(b, '\n'))
+	return err
+}
+
+// Close closes the underlying file.
+func (e *OTLPFileExporter) Close() error {
+	return e.file.Close()
+}
+
+type otlpKeyValue struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+func otlpRequest(serviceName string, span *Span) map[string]any {
+	s := map[string]any{
+		"traceId":           span.Context.TraceID.String(),
+		"spanId":            span.Context.SpanID.String(),
+		"name":              span.Name,
+		"kind":              int(span.Kind),
+		"startTimeUnixNano": strconv.FormatInt(span.StartTime.UnixNano(), 10),
+		"endTimeUnixNano":   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+		"attributes":        otlpAttributes(span.Attributes),
+		"status":            map[string]any{},
+	}
+	if span.ParentID.IsValid() {
+		s["parentSpanId"] = span.ParentID.String()
+	}
+	if span.Err != nil {
+		// STATUS_CODE_ERROR
+		s["status"] = map[string]any{"code": 2, "message": span.Err.Error()}
+	}
+	return map[string]any{
+		"resourceSpans": []any{
+			map[string]any{
+				"resource": map[string]any{
+					"attributes": otlpAttributes(map[string]any{"service.name": serviceName}),
+				},
+				"scopeSpans": []any{
+					map[string]any{
+						"scope": map[string]any{"name": "github.com/anthdm/superkit"},
+						"spans": []any{s},
+					},
+				},
+			},
+		},
+	}
+}
+
+func otlpAttributes(attrs map[string]any) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for key, value := range attrs {
+		var v map[string]any
+		switch value := value.(type) {
+		case string:
+			v = map[string]any{"stringValue": value}
+		case bool:
+			v = map[string]any{"boolValue": value}
+		case int:
+			v = map[string]any{"intValue": strconv.Itoa(value)}
+		case int64:
+			v = map[string]any{"intValue": strconv.FormatInt(value, 10)}
+		case float64:
+			v = map[string]any{"doubleValue": value}
+		default:
+			v = map[string]any{"stringValue": fmt.Sprint(value)}
+		}
+		kvs = append(kvs, otlpKeyValue{Key: key, Value: v})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs
+}
@@ -0,0 +1,232 @@
+package trace
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+// OpenDB opens a database like sql.Open, but every query, exec, prepare
+// and transaction is recorded as a span, as a child of the span in the
+// context passed to the *Context methods of the returned *sql.DB.
+func OpenDB(driverName string, dsn string) (*sql.DB, error) {
+	// sql.Open doesn't connect, it is the only public way to look up a
+	// registered driver by its name.
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	db.Close()
+
+	var connector driver.Connector
+	if dc, ok := d.(driver.DriverContext); ok {
+		connector, err = dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		connector = dsnConnector{dsn: dsn, driver: d}
+	}
+	return sql.OpenDB(&tracedConnector{
+		connector: connector,
+		system:    driverName,
+	}), nil
+}
+
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open(c.dsn) }
+func (c dsnConnector) Driver() driver.Driver                        { return c.driver }
+
+type tracedConnector struct {
+	connector driver.Connector
+	system    string
+}
+
+func (c *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{conn: conn, system: c.system}, nil
+}
+
+func (c *tracedConnector) Driver() driver.Driver { return c.connector.Driver() }
+
+func startQuery(ctx context.Context, system, op, query string) (context.Context, *Span) {
+	return Start(ctx, "db."+op,
+		WithKind(KindClient),
+		WithAttributes("db.system", system, "db.statement", query),
+	)
+}
+
+func endQuery(span *Span, err error) {
+	if err != nil && !errors.Is(err, driver.ErrSkip) {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+type tracedConn struct {
+	conn   driver.Conn
+	system string
+}
+
+func (c *tracedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (stmt driver.Stmt, err error) {
+	_, span := startQuery(ctx, c.system, "prepare", query)
+	defer func() { endQuery(span, err) }()
+	if cp, ok := c.conn.(driver.ConnPrepareContext); ok {
+		stmt, err = cp.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tracedStmt{stmt: stmt, query: query, system: c.system}, nil
+}
+
+func (c *tracedConn) Close() error { return c.conn.Close() }
+
+func (c *tracedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (tx driver.Tx, err error) {
+	_, span := startQuery(ctx, c.system, "begin", "")
+	defer func() { endQuery(span, err) }()
+	if cb, ok := c.conn.(driver.ConnBeginTx); ok {
+		return cb.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != 0 || opts.ReadOnly {
+		return nil, errors.New("trace: driver does not support transaction options")
+	}
+	return c.conn.Begin()
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (res driver.Result, err error) {
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	_, span := startQuery(ctx, c.system, "exec", query)
+	defer func() { endQuery(span, err) }()
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
+	queryer, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	_, span := startQuery(ctx, c.system, "query", query)
+	defer func() { endQuery(span, err) }()
+	return queryer.QueryContext(ctx, query, args)
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *tracedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type tracedStmt struct {
+	stmt   driver.Stmt
+	query  string
+	system string
+}
+
+func (s *tracedStmt) Close() error  { return s.stmt.Close() }
+func (s *tracedStmt) NumInput() int { return s.stmt.NumInput() }
+
+func (s *tracedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), toNamedValues(args))
+}
+
+func (s *tracedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), toNamedValues(args))
+}
+
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (res driver.Result, err error) {
+	_, span := startQuery(ctx, s.system, "exec", s.query)
+	defer func() { endQuery(span, err) }()
+	if execer, ok := s.stmt.(driver.StmtExecContext); ok {
+		return execer.ExecContext(ctx, args)
+	}
+	values, err := toValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return s.stmt.Exec(values)
+}
+
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (rows driver.Rows, err error) {
+	_, span := startQuery(ctx, s.system, "query", s.query)
+	defer func() { endQuery(span, err) }()
+	if queryer, ok := s.stmt.(driver.StmtQueryContext); ok {
+		return queryer.QueryContext(ctx, args)
+	}
+	values, err := toValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return s.stmt.Query(values)
+}
+
+func (s *tracedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func toNamedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+func toValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if len(arg.Name) > 0 {
+			return nil, fmt.Errorf("trace: driver does not support named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
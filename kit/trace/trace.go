@@ -0,0 +1,237 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SpanKind describes the relationship between the span and its parent.
+type SpanKind int
+
+const (
+	KindInternal SpanKind = iota + 1
+	KindServer
+	KindClient
+	KindProducer
+	KindConsumer
+)
+
+// TraceID is the W3C trace-id of a trace.
+type TraceID [16]byte
+
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+
+// IsValid returns true if the trace ID is not all zeros.
+func (id TraceID) IsValid() bool { return id != TraceID{} }
+
+// SpanID is the W3C parent-id of a span.
+type SpanID [8]byte
+
+func (id SpanID) String() string { return hex.EncodeToString(id[:]) }
+
+// IsValid returns true if the span ID is not all zeros.
+func (id SpanID) IsValid() bool { return id != SpanID{} }
+
+// SpanContext identifies a span across process boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid returns true if both the trace and span ID are valid.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Span represents a single operation within a trace. All methods
+// of Span are safe to call on a nil Span, which is what Start returns
+// when tracing is disabled.
+type Span struct {
+	mu sync.Mutex
+
+	Name       string
+	Context    SpanContext
+	ParentID   SpanID
+	Kind       SpanKind
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes map[string]any
+	Err        error
+
+	ended bool
+}
+
+// Exporter exports ended spans.
+type Exporter interface {
+	Export(span *Span) error
+}
+
+var (
+	exportermu sync.RWMutex
+	exporter   Exporter
+)
+
+// UseExporter sets the exporter ended spans are exported to. Passing
+// nil disables tracing.
+func UseExporter(e Exporter) {
+	exportermu.Lock()
+	defer exportermu.Unlock()
+	exporter = e
+}
+
+// Enabled returns true if an exporter is configured.
+func Enabled() bool {
+	exportermu.RLock()
+	defer exportermu.RUnlock()
+	return exporter != nil
+}
+
+type (
+	spanKey   struct{}
+	remoteKey struct{}
+)
+
+// Start starts a new span as a child of the span in ctx, or of the
+// remote span set with ContextWithRemote. If ctx has neither, a new
+// trace is started. The returned context holds the new span.
+//
+//	ctx, span := trace.Start(ctx, "createUser")
+//	defer span.End()
+//
+// When tracing is disabled, Start returns ctx and a nil Span.
+func Start(ctx context.Context, name string, opts ...func(*Span)) (context.Context, *Span) {
+	if !Enabled() {
+		return ctx, nil
+	}
+	span := &Span{
+		Name:       name,
+		Kind:       KindInternal,
+		StartTime:  time.Now(),
+		Attributes: map[string]any{},
+	}
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.Context.TraceID = parent.Context.TraceID
+		span.Context.Sampled = parent.Context.Sampled
+		span.ParentID = parent.Context.SpanID
+	} else if remote, ok := ctx.Value(remoteKey{}).(SpanContext); ok && remote.IsValid() {
+		span.Context.TraceID = remote.TraceID
+		span.Context.Sampled = remote.Sampled
+		span.ParentID = remote.SpanID
+	} else {
+		rand.Read(span.Context.TraceID[:])
+		span.Context.Sampled = true
+	}
+	rand.Read(span.Context.SpanID[:])
+	for _, opt := range opts {
+		opt(span)
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// WithKind sets the kind of the span.
+func WithKind(kind SpanKind) func(*Span) {
+	return func(span *Span) {
+		span.Kind = kind
+	}
+}
+
+// WithAttributes sets the given key value pairs as attributes of the span.
+//
+//	trace.Start(ctx, "query", trace.WithAttributes("db.system", "sqlite3"))
+func WithAttributes(kv ...any) func(*Span) {
+	return func(span *Span) {
+		setAttributes(span.Attributes, kv)
+	}
+}
+
+// SpanFromContext returns the current span in ctx or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemote returns a copy of ctx holding a remote parent span,
+// for example parsed from an incoming traceparent header. The next span
+// started from the returned context continues the remote trace.
+func ContextWithRemote(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// SetAttributes sets the given key value pairs as attributes of the span.
+func (s *Span) SetAttributes(kv ...any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	setAttributes(s.Attributes, kv)
+}
+
+// SetName renames the span, which is useful when the name is only known
+// at the end of an operation, like the route pattern of a request.
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Name = name
+}
+
+// RecordError marks the span as failed with the given error.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Err = err
+}
+
+// Duration returns the duration of an ended span.
+func (s *Span) Duration() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// End ends the span and exports it. Calling End more than once has no effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	s.mu.Unlock()
+
+	if !s.Context.Sampled {
+		return
+	}
+	exportermu.RLock()
+	e := exporter
+	exportermu.RUnlock()
+	if e == nil {
+		return
+	}
+	if err := e.Export(s); err != nil {
+		slog.Error("failed to export span", "err", err, "span", s.Name)
+	}
+}
+
+func setAttributes(attrs map[string]any, kv []any) {
+	for i := 0; i+1 < len(kv); i += 2 {
+		attrs[fmt.Sprint(kv[i])] = kv[i+1]
+	}
+}
@@ -0,0 +1,134 @@
+package trace
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+func (e *recordingExporter) Export(span *Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+	return nil
+}
+
+func TestParseTraceparent(t *testing.T) {
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.Nil(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+	assert.True(t, sc.Sampled)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00-zzf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}
+	for _, s := range invalid {
+		_, err := ParseTraceparent(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestStartDisabled(t *testing.T) {
+	UseExporter(nil)
+	ctx, span := Start(context.Background(), "foo")
+	assert.Nil(t, span)
+	assert.Nil(t, SpanFromContext(ctx))
+	// All methods are safe to call on a nil span.
+	span.SetAttributes("foo", "bar")
+	span.RecordError(errors.New("foo"))
+	span.End()
+}
+
+func TestStartChildAndRemote(t *testing.T) {
+	exp := &recordingExporter{}
+	UseExporter(exp)
+	defer UseExporter(nil)
+
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := ContextWithRemote(context.Background(), remote)
+
+	ctx, parent := Start(ctx, "parent", WithKind(KindServer))
+	_, child := Start(ctx, "child", WithAttributes("foo", 1))
+	child.RecordError(errors.New("boom"))
+	child.End()
+	parent.End()
+	parent.End()
+
+	assert.Len(t, exp.spans, 2)
+	assert.Equal(t, remote.TraceID, parent.Context.TraceID)
+	assert.Equal(t, remote.SpanID, parent.ParentID)
+	assert.Equal(t, parent.Context.TraceID, child.Context.TraceID)
+	assert.Equal(t, parent.Context.SpanID, child.ParentID)
+	assert.Equal(t, 1, child.Attributes["foo"])
+	assert.EqualError(t, child.Err, "boom")
+}
+
+func TestStdoutExporter(t *testing.T) {
+	var b strings.Builder
+	UseExporter(NewStdoutExporter(&b))
+	defer UseExporter(nil)
+
+	_, span := Start(context.Background(), "POST /signup", WithAttributes("http.status_code", 200))
+	span.End()
+	assert.Contains(t, b.String(), "POST /signup")
+	assert.Contains(t, b.String(), "http.status_code=200")
+	assert.Contains(t, b.String(), "parent=- ")
+}
+
+func TestOTLPFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	exp, err := NewOTLPFileExporter(path)
+	assert.Nil(t, err)
+	UseExporter(exp)
+	defer UseExporter(nil)
+
+	_, span := Start(context.Background(), "db.query", WithAttributes("db.statement", "select 1"))
+	span.End()
+	assert.Nil(t, exp.Close())
+
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	assert.True(t, scanner.Scan())
+
+	var req struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID    string `json:"traceId"`
+					Name       string `json:"name"`
+					Attributes []struct {
+						Key   string            `json:"key"`
+						Value map[string]string `json:"value"`
+					} `json:"attributes"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	assert.Nil(t, json.Unmarshal(scanner.Bytes(), &req))
+	s := req.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	assert.Equal(t, span.Context.TraceID.String(), s.TraceID)
+	assert.Equal(t, "db.query", s.Name)
+	assert.Equal(t, "select 1", s.Attributes[0].Value["stringValue"])
+}
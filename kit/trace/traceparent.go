@@ -0,0 +1,51 @@
+package trace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ParseTraceparent parses a W3C traceparent header value.
+//
+//	00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceparent(s string) (SpanContext, error) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 {
+		return sc, fmt.Errorf("invalid traceparent %q", s)
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	// Version ff is forbidden and version 00 has exactly 4 fields, future
+	// versions may add fields which we ignore.
+	if len(version) != 2 || version == "ff" || (version == "00" && len(parts) != 4) {
+		return sc, fmt.Errorf("invalid traceparent version %q", version)
+	}
+	if len(traceID) != 32 || len(spanID) != 16 || len(flags) != 2 {
+		return sc, fmt.Errorf("invalid traceparent %q", s)
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(traceID)); err != nil {
+		return sc, fmt.Errorf("invalid trace id: %w", err)
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(spanID)); err != nil {
+		return sc, fmt.Errorf("invalid span id: %w", err)
+	}
+	var f [1]byte
+	if _, err := hex.Decode(f[:], []byte(flags)); err != nil {
+		return sc, fmt.Errorf("invalid trace flags: %w", err)
+	}
+	if !sc.IsValid() {
+		return sc, fmt.Errorf("invalid traceparent %q: all zero id", s)
+	}
+	sc.Sampled = f[0]&0x01 == 0x01
+	return sc, nil
+}
+
+// Traceparent formats the span context as a W3C traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
@@ -0,0 +1,223 @@
+package validate
+
+import (
+	"encoding"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeLayouts are the layouts form values are parsed with into time.Time
+// fields, in order, unless the field has a layout tag. They cover RFC 3339
+// and the values of the HTML date, datetime-local and time inputs, which
+// are parsed as UTC.
+//
+//	StartsAt time.Time `form:"startsAt" layout:"02/01/2006"`
+var TimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	time.DateOnly,
+	"15:04",
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// parseRequest binds the form values of the request to the fields of v
+// with a form tag. Fields that are not in the form are left untouched,
+// hence a pointer field stays nil when its value is absent, while an empty
+// value sets a *string to the empty string. The sql.Null types are valid
+// once their value is not empty, fields of type []T take all the values
+// of a field and types implementing encoding.TextUnmarshaler unmarshal
+// themselves.
+func parseRequest(r *http.Request, v any) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/x-www-form-urlencoded" {
+		if err := r.ParseForm(); err != nil {
+			return fmt.Errorf("failed to parse form: %v", err)
+		}
+		val := reflect.ValueOf(v).Elem()
+		for i := 0; i < val.NumField(); i++ {
+			field := val.Type().Field(i)
+			formTag := field.Tag.Get("form")
+			if len(formTag) == 0 || !field.IsExported() {
+				continue
+			}
+			values, ok := r.Form[formTag]
+			if !ok {
+				continue
+			}
+			if err := bindField(val.Field(i), values, field.Tag.Get("layout")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func bindField(fieldVal reflect.Value, values []string, layout string) error {
+	var formValue string
+	if len(values) > 0 {
+		formValue = values[0]
+	}
+	typ := fieldVal.Type()
+	switch {
+	case typ.Kind() == reflect.Ptr:
+		if len(formValue) == 0 && typ.Elem().Kind() != reflect.String {
+			return nil
+		}
+		elem := reflect.New(typ.Elem())
+		if err := bindField(elem.Elem(), values, layout); err != nil {
+			return err
+		}
+		fieldVal.Set(elem)
+		return nil
+	case typ == timeType:
+		if len(formValue) == 0 {
+			return nil
+		}
+		t, err := parseTime(formValue, layout)
+		if err != nil {
+			return err
+		}
+		fieldVal.Set(reflect.ValueOf(t))
+		return nil
+	case isNullType(typ):
+		if len(formValue) == 0 {
+			fieldVal.SetZero()
+			return nil
+		}
+		if err := bindField(fieldVal.Field(0), values, layout); err != nil {
+			return err
+		}
+		fieldVal.Field(1).SetBool(true)
+		return nil
+	case reflect.PointerTo(typ).Implements(textUnmarshalerType):
+		if len(formValue) == 0 {
+			return nil
+		}
+		if err := fieldVal.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(formValue)); err != nil {
+			return fmt.Errorf("failed to parse %s: %v", typ, err)
+		}
+		return nil
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8:
+		slice := reflect.MakeSlice(typ, 0, len(values))
+		for _, value := range values {
+			if len(value) == 0 {
+				continue
+			}
+			elem := reflect.New(typ.Elem()).Elem()
+			if err := bindField(elem, []string{value}, layout); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		fieldVal.Set(slice)
+		return nil
+	}
+
+	if len(formValue) == 0 {
+		return nil
+	}
+	switch fieldVal.Kind() {
+	case reflect.Bool:
+		// There are cases where frontend libraries use "on" as the bool value
+		// think about toggles. Hence, let's try this first.
+		if formValue == "on" {
+			fieldVal.SetBool(true)
+		} else if formValue == "off" {
+			fieldVal.SetBool(false)
+		} else {
+			boolVal, err := strconv.ParseBool(formValue)
+			if err != nil {
+				return fmt.Errorf("failed to parse bool: %v", err)
+			}
+			fieldVal.SetBool(boolVal)
+		}
+	case reflect.String:
+		fieldVal.SetString(formValue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intVal, err := strconv.ParseInt(formValue, 10, typ.Bits())
+		if err != nil {
+			return fmt.Errorf("failed to parse int: %v", err)
+		}
+		fieldVal.SetInt(intVal)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		uintVal, err := strconv.ParseUint(formValue, 10, typ.Bits())
+		if err != nil {
+			return fmt.Errorf("failed to parse int: %v", err)
+		}
+		fieldVal.SetUint(uintVal)
+	case reflect.Float32, reflect.Float64:
+		floatVal, err := strconv.ParseFloat(formValue, typ.Bits())
+		if err != nil {
+			return fmt.Errorf("failed to parse float: %v", err)
+		}
+		fieldVal.SetFloat(floatVal)
+	default:
+		return fmt.Errorf("unsupported kind %s", fieldVal.Kind())
+	}
+	return nil
+}
+
+func parseTime(value, layout string) (time.Time, error) {
+	layouts := TimeLayouts
+	if len(layout) > 0 {
+		layouts = []string{layout}
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse time: %q does not match %s", value, strings.Join(layouts, ", "))
+}
+
+// isNullType reports whether the type is one of the sql.Null types, like
+// sql.NullString or sql.Null[T], which hold a value and a Valid flag.
+func isNullType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct &&
+		typ.PkgPath() == "database/sql" &&
+		strings.HasPrefix(typ.Name(), "Null") &&
+		typ.NumField() == 2 &&
+		typ.Field(1).Name == "Valid"
+}
+
+// fieldValue returns the value rules validate for v. Pointers are
+// dereferenced and sql.Null types unwrapped, a nil pointer or an invalid
+// sql.Null is absent and reported as such.
+func fieldValue(v reflect.Value) (value any, present bool) {
+	if !v.IsValid() {
+		return nil, true
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if isNullType(v.Type()) {
+		if !v.Field(1).Bool() {
+			return nil, false
+		}
+		v = v.Field(0)
+	}
+	return v.Interface(), true
+}
+
+// valueType returns the type of the values fieldValue returns for the
+// given field type.
+func valueType(typ reflect.Type) reflect.Type {
+	typ = indirect(typ)
+	if isNullType(typ) {
+		return typ.Field(0).Type
+	}
+	return typ
+}
@@ -0,0 +1,124 @@
+package validate
+
+import (
+	"database/sql"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFormRequest(t *testing.T, values url.Values) *http.Request {
+	req, err := http.NewRequest("POST", "http://foo.com", strings.NewReader(values.Encode()))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	return req
+}
+
+func TestRequestBinding(t *testing.T) {
+	type Form struct {
+		Nickname  *string         `form:"nickname"`
+		Bio       *string         `form:"bio"`
+		Age       *int            `form:"age"`
+		Phone     sql.NullString  `form:"phone"`
+		Fax       sql.NullString  `form:"fax"`
+		Score     sql.NullInt64   `form:"score"`
+		Birthday  time.Time       `form:"birthday"`
+		StartsAt  sql.NullTime    `form:"startsAt"`
+		EndsAt    time.Time       `form:"endsAt" layout:"02/01/2006"`
+		Tags      []string        `form:"tags"`
+		Ratings   []int           `form:"ratings"`
+		IP        netip.Addr      `form:"ip"`
+		Small     int8            `form:"small"`
+		Active    bool            `form:"active"`
+		Newsleter bool            `form:"newsletter"`
+		Ratio     float32         `form:"ratio"`
+		Missing   *sql.NullString `form:"missing"`
+	}
+	values := url.Values{}
+	values.Set("bio", "")
+	values.Set("age", "")
+	values.Set("phone", "+32123")
+	values.Set("fax", "")
+	values.Set("score", "42")
+	values.Set("birthday", "1990-04-02")
+	values.Set("startsAt", "2024-06-01T09:30")
+	values.Set("endsAt", "31/12/2024")
+	values["tags"] = []string{"go", "", "templ"}
+	values["ratings"] = []string{"4", "5"}
+	values.Set("ip", "127.0.0.1")
+	values.Set("small", "12")
+	values.Set("newsletter", "off")
+	values.Set("active", "on")
+	values.Set("ratio", "0.5")
+
+	var form Form
+	errors, ok := Request(newFormRequest(t, values), &form, nil)
+	assert.True(t, ok, errors)
+
+	assert.Nil(t, form.Nickname)
+	assert.NotNil(t, form.Bio)
+	assert.Equal(t, "", *form.Bio)
+	assert.Nil(t, form.Age)
+	assert.Equal(t, sql.NullString{String: "+32123", Valid: true}, form.Phone)
+	assert.False(t, form.Fax.Valid)
+	assert.Equal(t, sql.NullInt64{Int64: 42, Valid: true}, form.Score)
+	assert.Equal(t, time.Date(1990, 4, 2, 0, 0, 0, 0, time.UTC), form.Birthday)
+	assert.Equal(t, time.Date(2024, 6, 1, 9, 30, 0, 0, time.UTC), form.StartsAt.Time)
+	assert.True(t, form.StartsAt.Valid)
+	assert.Equal(t, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), form.EndsAt)
+	assert.Equal(t, []string{"go", "templ"}, form.Tags)
+	assert.Equal(t, []int{4, 5}, form.Ratings)
+	assert.Equal(t, netip.MustParseAddr("127.0.0.1"), form.IP)
+	assert.Equal(t, int8(12), form.Small)
+	assert.True(t, form.Active)
+	assert.False(t, form.Newsleter)
+	assert.Equal(t, float32(0.5), form.Ratio)
+	assert.Nil(t, form.Missing)
+}
+
+func TestRequestBindingErrors(t *testing.T) {
+	type Form struct {
+		Small    int8      `form:"small"`
+		StartsAt time.Time `form:"startsAt"`
+	}
+	var form Form
+	errors, ok := Request(newFormRequest(t, url.Values{"small": {"300"}}), &form, nil)
+	assert.False(t, ok)
+	assert.True(t, errors.Has("_error"))
+
+	errors, ok = Request(newFormRequest(t, url.Values{"startsAt": {"tomorrow"}}), &form, nil)
+	assert.False(t, ok)
+	assert.True(t, errors.Has("_error"))
+}
+
+func TestOptionalFieldRules(t *testing.T) {
+	type Form struct {
+		Nickname *string       `validate:"min=3"`
+		Website  *string       `validate:"required,url"`
+		Age      *int          `validate:"gte=18"`
+		Score    sql.NullInt64 `validate:"required,lte=100"`
+		Accepted bool          `validate:"required"`
+		Count    int           `validate:"required"`
+	}
+	short, site, age := "ab", "http://foo.com", 16
+	errors, ok := Struct(Form{Nickname: &short, Website: &site, Age: &age, Score: sql.NullInt64{Int64: 120, Valid: true}})
+	assert.False(t, ok)
+	assert.True(t, errors.Has("nickname"))
+	assert.False(t, errors.Has("website"))
+	assert.True(t, errors.Has("age"))
+	assert.Equal(t, []string{"should be lesser or equal than 100"}, errors["score"])
+	assert.Equal(t, []string{"is a required field"}, errors["accepted"])
+	assert.Equal(t, []string{"is a required field"}, errors["count"])
+
+	// Absent values are only checked by required rules.
+	errors, ok = Struct(Form{Accepted: true, Count: 1})
+	assert.False(t, ok)
+	assert.Len(t, errors, 2)
+	assert.Equal(t, []string{"is a required field"}, errors["website"])
+	assert.Equal(t, []string{"is a required field"}, errors["score"])
+}
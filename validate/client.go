@@ -0,0 +1,188 @@
+package validate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// clientRule sets the HTML5 attributes of an input that enforce the rule
+// in the browser.
+type clientRule func(set RuleSet, attrs templ.Attributes)
+
+// clientRules holds the rules that have an HTML5 equivalent. Rules without
+// one, like containsUpper, are only validated on the server and by the
+// JavaScript libraries reading the Description of a Schema.
+var clientRules = map[string]clientRule{
+	"required": setAttr("required", true),
+	"email":    setAttr("type", "email"),
+	"url":      setAttr("type", "url"),
+	"min":      ruleAttr("minlength"),
+	"max":      ruleAttr("maxlength"),
+	"gte":      ruleAttr("min"),
+	"lte":      ruleAttr("max"),
+	"gt": func(set RuleSet, attrs templ.Attributes) {
+		if n, ok := set.RuleValue.(int); ok {
+			attrs["min"] = fmt.Sprint(n + 1)
+		}
+	},
+	"lt": func(set RuleSet, attrs templ.Attributes) {
+		if n, ok := set.RuleValue.(int); ok {
+			attrs["max"] = fmt.Sprint(n - 1)
+		}
+	},
+	"phone": setAttr("type", "tel"),
+	"between": func(set RuleSet, attrs templ.Attributes) {
+		val := reflect.ValueOf(set.RuleValue)
+		attrs["min"] = fmt.Sprint(val.Index(0).Interface())
+		attrs["max"] = fmt.Sprint(val.Index(1).Interface())
+	},
+	"match": ruleAttr("pattern"),
+	"startsWith": func(set RuleSet, attrs templ.Attributes) {
+		attrs["pattern"] = regexp.QuoteMeta(fmt.Sprint(set.RuleValue)) + ".*"
+	},
+	"endsWith": func(set RuleSet, attrs templ.Attributes) {
+		attrs["pattern"] = ".*" + regexp.QuoteMeta(fmt.Sprint(set.RuleValue))
+	},
+	"in": func(set RuleSet, attrs templ.Attributes) {
+		val := reflect.ValueOf(set.RuleValue)
+		values := make([]string, val.Len())
+		for i := range values {
+			values[i] = regexp.QuoteMeta(fmt.Sprint(val.Index(i).Interface()))
+		}
+		attrs["pattern"] = strings.Join(values, "|")
+	},
+}
+
+func setAttr(name string, value any) clientRule {
+	return func(_ RuleSet, attrs templ.Attributes) {
+		attrs[name] = value
+	}
+}
+
+func ruleAttr(name string) clientRule {
+	return func(set RuleSet, attrs templ.Attributes) {
+		attrs[name] = fmt.Sprint(set.RuleValue)
+	}
+}
+
+// StructSchema returns the Schema built from the validate tags of v, a
+// struct or a pointer to one, combined with the rules of the given
+// schemas. It holds the same rules Struct and Request validate v with.
+func StructSchema(v any, schemas ...Schema) Schema {
+	return combine(structSchema(reflect.TypeOf(v)), schemas...)
+}
+
+// Attributes returns the HTML5 validation attributes of the field, like
+// required, minlength or type="email", to spread on its input.
+//
+//	<input { signupRules.Attributes("email")... } name="email"/>
+func (schema Schema) Attributes(field string) templ.Attributes {
+	attrs := templ.Attributes{}
+	field = normalizePath(field)
+	for path, ruleSets := range schema {
+		if normalizePath(path) != field {
+			continue
+		}
+		for _, set := range ruleSets {
+			if set.ValidateContextFunc != nil {
+				continue
+			}
+			if apply, ok := clientRules[set.Name]; ok {
+				apply(set, attrs)
+			}
+		}
+	}
+	return attrs
+}
+
+// Attrs returns an option adding the validation attributes of the field,
+// see Attributes, to the attributes of a ui component.
+//
+//	input.Input(signupRules.Attrs("email"))
+func (schema Schema) Attrs(field string) func(*templ.Attributes) {
+	return func(attrs *templ.Attributes) {
+		for name, value := range schema.Attributes(field) {
+			(*attrs)[name] = value
+		}
+	}
+}
+
+// RuleDescription describes a rule of a Schema for client-side validation.
+type RuleDescription struct {
+	Name    string            `json:"name"`
+	Value   any               `json:"value,omitempty"`
+	Message string            `json:"message"`
+	Rules   []RuleDescription `json:"rules,omitempty"`
+}
+
+// Description describes the rules of the schema keyed by the field path,
+// spelled like the keys of Errors. Rules that need a context, like Unique,
+// can't be validated in the browser and are left out.
+func (schema Schema) Description() map[string][]RuleDescription {
+	desc := map[string][]RuleDescription{}
+	for path, ruleSets := range schema {
+		key, name := descriptionKey(path)
+		desc[key] = describe(ruleSets, key, name)
+	}
+	return desc
+}
+
+// MarshalJSON encodes the Description of the schema, for JavaScript
+// validation libraries.
+//
+//	<div data-rules={ templ.JSONString(signupRules) }>
+func (schema Schema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(schema.Description())
+}
+
+func describe(ruleSets []RuleSet, key, name string) []RuleDescription {
+	desc := []RuleDescription{}
+	for _, set := range ruleSets {
+		if set.ValidateContextFunc != nil {
+			continue
+		}
+		if len(set.each) > 0 {
+			desc = append(desc, RuleDescription{
+				Name:  set.Name,
+				Rules: describe(set.each, key, name),
+			})
+			continue
+		}
+		set.FieldName = name
+		set.FieldLabel = fieldLabel(reflect.Value{}, name)
+		desc = append(desc, RuleDescription{
+			Name:    set.Name,
+			Value:   set.RuleValue,
+			Message: message(context.Background(), set, key),
+		})
+	}
+	return desc
+}
+
+// descriptionKey returns the key of the path in Errors and the name of
+// the struct field it ends with.
+func descriptionKey(path string) (key, name string) {
+	tokens, err := parsePath(path)
+	if err != nil {
+		return path, path
+	}
+	var b strings.Builder
+	for _, tok := range tokens {
+		if len(tok.field) == 0 {
+			b.WriteString("[" + tok.index + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		name = structFieldName(tok.field)
+		b.WriteString(errorFieldName(name))
+	}
+	return b.String(), name
+}
@@ -0,0 +1,63 @@
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaAttributes(t *testing.T) {
+	type Signup struct {
+		Email    string `validate:"required,email"`
+		Password string `validate:"required,min=7,max=50,containsUpper"`
+		Age      int    `validate:"gte=18,lt=130"`
+		Currency string `validate:"in=eur|usd"`
+	}
+	schema := StructSchema(Signup{}, Schema{
+		"email": Rules(Unique(nil, "users", "email")),
+	})
+	assert.Equal(t, templ.Attributes{"required": true, "type": "email"}, schema.Attributes("email"))
+	assert.Equal(t, templ.Attributes{"required": true, "minlength": "7", "maxlength": "50"}, schema.Attributes("password"))
+	assert.Equal(t, templ.Attributes{"min": "18", "max": "129"}, schema.Attributes("age"))
+	assert.Equal(t, templ.Attributes{"pattern": "eur|usd"}, schema.Attributes("currency"))
+	assert.Equal(t, templ.Attributes{}, schema.Attributes("unknown"))
+	assert.Equal(t, templ.Attributes{"min": "1", "max": "5", "type": "tel"}, Schema{
+		"contact": Rules(Between(1, 5), Phone),
+	}.Attributes("contact"))
+
+	attrs := templ.Attributes{"class": "input"}
+	schema.Attrs("email")(&attrs)
+	assert.Equal(t, templ.Attributes{"class": "input", "required": true, "type": "email"}, attrs)
+}
+
+func TestSchemaDescription(t *testing.T) {
+	type Item struct {
+		Quantity int `validate:"gte=1"`
+	}
+	type Order struct {
+		FirstName string   `validate:"min=2"`
+		Tags      []string `validate:"each,max=20"`
+		Items     []Item
+	}
+	schema := StructSchema(Order{}, Schema{
+		"firstName": Rules(Unique(nil, "users", "first_name")),
+	})
+	desc := schema.Description()
+	assert.Equal(t, []RuleDescription{
+		{Name: "min", Value: 2, Message: "should be at least 2 characters long"},
+	}, desc["firstName"])
+	assert.Equal(t, []RuleDescription{
+		{Name: "each", Rules: []RuleDescription{
+			{Name: "max", Value: 20, Message: "should be maximum 20 characters long"},
+		}},
+	}, desc["tags"])
+	assert.Equal(t, []RuleDescription{
+		{Name: "gte", Value: 1, Message: "should be greater or equal than 1"},
+	}, desc["items[].quantity"])
+
+	b, err := json.Marshal(Schema{"firstName": Rules(Required.Message("{label} is required"))})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"firstName":[{"name":"required","message":"First name is required"}]}`, string(b))
+}
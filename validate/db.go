@@ -0,0 +1,76 @@
+package validate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Querier queries a single row. It is implemented by *sql.DB, *sql.Tx and
+// *sql.Conn, as well as by the ConnPool of a gorm DB.
+type Querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Unique validates that no row of the table has the value of the field
+// in the given column. Empty values are not checked, combine it with
+// Required if needed. Use ValidateContext or Request to bound the query
+// with a timeout.
+//
+//	"email": Rules(Email, Unique(db, "users", "email")),
+func Unique(db Querier, table, column string) RuleSet {
+	return contextRule("unique", []string{table, column}, "is already taken",
+		func(ctx context.Context, set RuleSet) (bool, error) {
+			if !isPresent(set.FieldValue) {
+				return true, nil
+			}
+			found, err := rowExists(ctx, db, table, column, set.FieldValue)
+			return !found, err
+		})
+}
+
+// Exists validates that a row of the table has the value of the field in
+// the given column, like the ID of a referenced row. Empty values are not
+// checked, combine it with Required if needed.
+//
+//	"planID": Rules(Exists(db, "plans", "id")),
+func Exists(db Querier, table, column string) RuleSet {
+	return contextRule("exists", []string{table, column}, "does not exist",
+		func(ctx context.Context, set RuleSet) (bool, error) {
+			if !isPresent(set.FieldValue) {
+				return true, nil
+			}
+			return rowExists(ctx, db, table, column, set.FieldValue)
+		})
+}
+
+// contextRule returns a RuleSet validating with fn. Its ValidateFunc, for
+// callers without a context, treats errors as invalid.
+func contextRule(name string, value any, msg string, fn func(context.Context, RuleSet) (bool, error)) RuleSet {
+	return RuleSet{
+		Name:                name,
+		RuleValue:           value,
+		ValidateContextFunc: fn,
+		ValidateFunc: func(set RuleSet) bool {
+			ok, err := fn(context.Background(), set)
+			return ok && err == nil
+		},
+		MessageFunc: func(set RuleSet) string {
+			return msg
+		},
+	}
+}
+
+func rowExists(ctx context.Context, db Querier, table, column string, value any) (bool, error) {
+	query := fmt.Sprintf("select 1 from %s where %s = ? limit 1", table, column)
+	var one int
+	err := db.QueryRowContext(ctx, query, value).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to query %s.%s: %w", table, column, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,66 @@
+package validate
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	assert.Nil(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`
+		create table users(id integer primary key, email text not null);
+		create table plans(id integer primary key);
+		insert into users(email) values ('taken@bar.com');
+		insert into plans(id) values (1);
+	`)
+	assert.Nil(t, err)
+	return db
+}
+
+func TestUniqueAndExists(t *testing.T) {
+	db := newTestDB(t)
+	type Signup struct {
+		Email  string
+		PlanID int
+	}
+	schema := Schema{
+		"email":  Rules(Unique(db, "users", "email")),
+		"planID": Rules(Exists(db, "plans", "id")),
+	}
+	errors, ok := ValidateContext(context.Background(), Signup{Email: "free@bar.com", PlanID: 1}, schema)
+	assert.True(t, ok)
+	assert.Empty(t, errors)
+
+	errors, ok = ValidateContext(context.Background(), Signup{Email: "taken@bar.com", PlanID: 2}, schema)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"is already taken"}, errors["email"])
+	assert.Equal(t, []string{"does not exist"}, errors["planID"])
+
+	// Empty values are left to Required.
+	_, ok = Validate(Signup{}, schema)
+	assert.True(t, ok)
+}
+
+func TestValidateContextCanceled(t *testing.T) {
+	db := newTestDB(t)
+	type Signup struct {
+		Email string
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+	errors, ok := ValidateContext(ctx, Signup{Email: "free@bar.com"}, Schema{
+		"email": Rules(Unique(db, "users", "email")),
+	})
+	assert.False(t, ok)
+	assert.False(t, errors.Has("email"))
+	assert.True(t, errors.Has("_error"))
+}
@@ -0,0 +1,236 @@
+package validate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	uuidRegex     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hexColorRegex = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	slugRegex     = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	phoneRegex    = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	ibanRegex     = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+	semverRegex   = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+)
+
+// stringRule returns a RuleSet validating string fields with fn. Fields
+// of other types are invalid.
+func stringRule(name string, value any, msg string, fn func(string) bool) RuleSet {
+	return RuleSet{
+		Name:      name,
+		RuleValue: value,
+		ValidateFunc: func(set RuleSet) bool {
+			str, ok := set.FieldValue.(string)
+			if !ok {
+				return false
+			}
+			return fn(str)
+		},
+		MessageFunc: func(set RuleSet) string {
+			return msg
+		},
+	}
+}
+
+// UUID validates a UUID in its canonical form, like
+// "f47ac10b-58cc-4372-a567-0e02b2c3d479".
+var UUID = stringRule("uuid", nil, "is not a valid UUID", uuidRegex.MatchString)
+
+var IPv4 = stringRule("ipv4", nil, "is not a valid IPv4 address", func(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+})
+
+var IPv6 = stringRule("ipv6", nil, "is not a valid IPv6 address", func(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && strings.Contains(s, ":")
+})
+
+// CIDR validates an IPv4 or IPv6 network in CIDR notation, like
+// "192.168.0.0/16".
+var CIDR = stringRule("cidr", nil, "is not a valid CIDR notation", func(s string) bool {
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
+})
+
+// Alpha validates that the string only holds letters, of any alphabet.
+var Alpha = stringRule("alpha", nil, "must only contain letters", func(s string) bool {
+	for _, ch := range s {
+		if !unicode.IsLetter(ch) {
+			return false
+		}
+	}
+	return true
+})
+
+// Alphanumeric validates that the string only holds letters and digits.
+var Alphanumeric = stringRule("alphanumeric", nil, "must only contain letters and digits", func(s string) bool {
+	for _, ch := range s {
+		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) {
+			return false
+		}
+	}
+	return true
+})
+
+var ASCII = stringRule("ascii", nil, "must only contain ASCII characters", func(s string) bool {
+	for _, ch := range s {
+		if ch > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+})
+
+// JSON validates that the string is valid JSON.
+var JSON = stringRule("json", nil, "is not valid JSON", func(s string) bool {
+	return json.Valid([]byte(s))
+})
+
+// Base64 validates a standard, padded base64 encoded string.
+var Base64 = stringRule("base64", nil, "is not valid base64", func(s string) bool {
+	_, err := base64.StdEncoding.DecodeString(s)
+	return err == nil
+})
+
+// HexColor validates a CSS hex color, like "#fff" or "#1e90ff".
+var HexColor = stringRule("hexColor", nil, "is not a valid hex color", hexColorRegex.MatchString)
+
+// Slug validates a URL slug of lowercase letters and digits separated by
+// single dashes, like "hello-world-2".
+var Slug = stringRule("slug", nil, "is not a valid slug", slugRegex.MatchString)
+
+// Phone validates a phone number in the E.164 format, like "+32470123456".
+var Phone = stringRule("phone", nil, "is not a valid phone number", phoneRegex.MatchString)
+
+// CreditCard validates a credit card number with the Luhn checksum.
+// Spaces and dashes between the digits are allowed.
+var CreditCard = stringRule("creditCard", nil, "is not a valid credit card number", func(s string) bool {
+	s = strings.NewReplacer(" ", "", "-", "").Replace(s)
+	if len(s) < 12 || len(s) > 19 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		digit := int(s[len(s)-1-i] - '0')
+		if digit < 0 || digit > 9 {
+			return false
+		}
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+})
+
+// IBAN validates an international bank account number with its check
+// digits. Spaces between the groups are allowed.
+var IBAN = stringRule("iban", nil, "is not a valid IBAN", func(s string) bool {
+	s = strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	if !ibanRegex.MatchString(s) {
+		return false
+	}
+	// Move the country code and check digits to the end and replace the
+	// letters by numbers, A being 10, the result modulo 97 must be 1.
+	var digits strings.Builder
+	for _, ch := range s[4:] + s[:4] {
+		if ch >= 'A' {
+			fmt.Fprint(&digits, ch-'A'+10)
+		} else {
+			digits.WriteRune(ch)
+		}
+	}
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && n.Mod(n, big.NewInt(97)).Int64() == 1
+})
+
+// Semver validates a semantic version, like "1.4.2" or "2.0.0-rc.1".
+var Semver = stringRule("semver", nil, "is not a valid semantic version", semverRegex.MatchString)
+
+// Country validates an ISO 3166-1 alpha-2 country code, like "BE".
+var Country = stringRule("country", nil, "is not a valid country code", func(s string) bool {
+	return countryCodes[s]
+})
+
+// Currency validates an ISO 4217 currency code, like "EUR".
+var Currency = stringRule("currency", nil, "is not a valid currency code", func(s string) bool {
+	return currencyCodes[s]
+})
+
+// Match validates that the string matches the regular expression.
+//
+//	"username": Rules(Match(regexp.MustCompile(`^[a-z0-9_]+$`))),
+func Match(re *regexp.Regexp) RuleSet {
+	return stringRule("match", re.String(), "has an invalid format", re.MatchString)
+}
+
+// StartsWith validates that the string starts with the given prefix.
+func StartsWith(prefix string) RuleSet {
+	return stringRule("startsWith", prefix, fmt.Sprintf("should start with %s", prefix), func(s string) bool {
+		return strings.HasPrefix(s, prefix)
+	})
+}
+
+// EndsWith validates that the string ends with the given suffix.
+func EndsWith(suffix string) RuleSet {
+	return stringRule("endsWith", suffix, fmt.Sprintf("should end with %s", suffix), func(s string) bool {
+		return strings.HasSuffix(s, suffix)
+	})
+}
+
+// Date validates that the string is a date or time in the given layout.
+//
+//	"birthday": Rules(Date(time.DateOnly)),
+func Date(layout string) RuleSet {
+	return stringRule("date", layout, fmt.Sprintf("is not a valid date in the format %s", layout), func(s string) bool {
+		_, err := time.Parse(layout, s)
+		return err == nil
+	})
+}
+
+// Between validates that the number is between min and max, inclusive.
+func Between[T Numeric](min, max T) RuleSet {
+	return RuleSet{
+		Name:      "between",
+		RuleValue: []T{min, max},
+		ValidateFunc: func(set RuleSet) bool {
+			n, ok := set.FieldValue.(T)
+			return ok && n >= min && n <= max
+		},
+		MessageFunc: func(set RuleSet) string {
+			return fmt.Sprintf("should be between %v and %v", min, max)
+		},
+	}
+}
+
+// NotIn validates that the field is none of the given values.
+func NotIn[T any](values []T) RuleSet {
+	return RuleSet{
+		Name:      "notIn",
+		RuleValue: values,
+		ValidateFunc: func(set RuleSet) bool {
+			for _, value := range values {
+				if reflect.DeepEqual(set.FieldValue, value) {
+					return false
+				}
+			}
+			return true
+		},
+		MessageFunc: func(set RuleSet) string {
+			return fmt.Sprintf("should not be in %v", values)
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package validate
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatRules(t *testing.T) {
+	tests := []struct {
+		rule    RuleSet
+		valid   []any
+		invalid []any
+	}{
+		{UUID, []any{"f47ac10b-58cc-4372-a567-0e02b2c3d479"}, []any{"f47ac10b58cc4372a5670e02b2c3d479", "xyz", 12}},
+		{IPv4, []any{"192.168.0.1"}, []any{"256.0.0.1", "::1", "::ffff:192.168.0.1"}},
+		{IPv6, []any{"::1", "2001:db8::68"}, []any{"192.168.0.1", "2001:db8::g"}},
+		{CIDR, []any{"10.0.0.0/8", "2001:db8::/32"}, []any{"10.0.0.0", "10.0.0.0/33"}},
+		{Alpha, []any{"héllo"}, []any{"hello1", "hello world"}},
+		{Alphanumeric, []any{"héllo1"}, []any{"hello-1"}},
+		{ASCII, []any{"hello, world!"}, []any{"héllo"}},
+		{JSON, []any{`{"a":[1,2]}`, `"str"`}, []any{`{"a":}`}},
+		{Base64, []any{"aGVsbG8="}, []any{"aGVsbG8", "$$"}},
+		{HexColor, []any{"#fff", "#1E90FF", "#1e90ff80"}, []any{"fff", "#ggg", "#12345"}},
+		{Slug, []any{"hello-world-2"}, []any{"Hello-World", "hello--world", "-hello"}},
+		{Phone, []any{"+32470123456"}, []any{"0470123456", "+0470123456", "+3247012345678901"}},
+		{CreditCard, []any{"4111 1111 1111 1111", "5500-0000-0000-0004"}, []any{"4111 1111 1111 1112", "4111"}},
+		{IBAN, []any{"BE68 5390 0754 7034", "GB82WEST12345698765432"}, []any{"BE68 5390 0754 7035", "BE68"}},
+		{Semver, []any{"1.4.2", "2.0.0-rc.1+build.5"}, []any{"1.4", "01.4.2", "v1.4.2"}},
+		{Country, []any{"BE", "US"}, []any{"be", "XX"}},
+		{Currency, []any{"EUR", "USD"}, []any{"eur", "EURO"}},
+		{Match(regexp.MustCompile(`^[a-z_]+$`)), []any{"snake_case"}, []any{"camelCase"}},
+		{StartsWith("sk_"), []any{"sk_live"}, []any{"pk_live"}},
+		{EndsWith(".pdf"), []any{"terms.pdf"}, []any{"terms.doc"}},
+		{Date(time.DateOnly), []any{"2024-02-29"}, []any{"2023-02-29", "29/02/2024"}},
+		{Between(1, 10), []any{1, 10}, []any{0, 11}},
+		{Between(0.5, 1.5), []any{1.0}, []any{2.0}},
+		{NotIn([]string{"admin", "root"}), []any{"alice"}, []any{"admin"}},
+		{Min(3), []any{"äöü"}, []any{"äö"}},
+		{Max(3), []any{"äöü"}, []any{"äöüß"}},
+	}
+	for _, test := range tests {
+		for _, v := range test.valid {
+			test.rule.FieldValue = v
+			assert.True(t, test.rule.ValidateFunc(test.rule), "%s: %v should be valid", test.rule.Name, v)
+		}
+		for _, v := range test.invalid {
+			test.rule.FieldValue = v
+			assert.False(t, test.rule.ValidateFunc(test.rule), "%s: %v should be invalid", test.rule.Name, v)
+		}
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	type Account struct {
+		ID       string  `validate:"uuid"`
+		Username string  `validate:"alphanumeric,min=3,notIn=admin|root"`
+		Token    string  `validate:"startsWith=sk_,match=^sk_[a-z]+$"`
+		Birthday string  `validate:"date"`
+		Age      int     `validate:"between=18|130"`
+		Score    float32 `validate:"between=0|1"`
+		Country  string  `validate:"country"`
+	}
+	errors, ok := Struct(Account{
+		ID:       "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		Username: "alice",
+		Token:    "sk_live",
+		Birthday: "1990-04-01",
+		Age:      34,
+		Score:    0.5,
+		Country:  "BE",
+	})
+	assert.True(t, ok)
+	assert.Empty(t, errors)
+
+	errors, ok = Struct(Account{
+		ID:       "nope",
+		Username: "admin",
+		Token:    "sk_LIVE",
+		Birthday: "01/04/1990",
+		Age:      12,
+		Score:    1.5,
+		Country:  "XX",
+	})
+	assert.False(t, ok)
+	assert.Equal(t, Errors{
+		"iD":       {"is not a valid UUID"},
+		"username": {"should not be in [admin root]"},
+		"token":    {"has an invalid format"},
+		"birthday": {"is not a valid date in the format 2006-01-02"},
+		"age":      {"should be between 18 and 130"},
+		"score":    {"should be between 0 and 1"},
+		"country":  {"is not a valid country code"},
+	}, errors)
+}
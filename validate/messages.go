@@ -0,0 +1,138 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Translator translates the message of a failed rule, for example based
+// on the locale in ctx. It receives the name of the rule and its message
+// before the placeholders are replaced, and returns the translation.
+type Translator func(ctx context.Context, rule, message string) string
+
+var (
+	messagemu  sync.RWMutex
+	messages   = map[string]string{}
+	translator Translator
+)
+
+var placeholderRegex = regexp.MustCompile(`\{(\w+)\}`)
+
+// SetMessage overrides the message of the rule with the given name for
+// all fields, unless a RuleSet has its own message. Messages may contain
+// the placeholders {label}, {field}, {value} and {param}, as well as the
+// name of the rule for its parameter, like {min}.
+//
+//	validate.SetMessage("min", "{label} must be at least {min} characters")
+func SetMessage(rule, message string) {
+	messagemu.Lock()
+	defer messagemu.Unlock()
+	messages[rule] = message
+}
+
+// SetTranslator sets the Translator all messages are passed through.
+func SetTranslator(t Translator) {
+	messagemu.Lock()
+	defer messagemu.Unlock()
+	translator = t
+}
+
+// message returns the message of the failed rule with its placeholders
+// replaced.
+func message(ctx context.Context, set RuleSet, key string) string {
+	messagemu.RLock()
+	override, ok := messages[set.Name]
+	translate := translator
+	messagemu.RUnlock()
+
+	var msg string
+	switch {
+	case len(set.ErrorMessage) > 0:
+		msg = set.ErrorMessage
+	case ok:
+		msg = override
+	default:
+		msg = set.MessageFunc(set)
+	}
+	if translate != nil {
+		msg = translate(ctx, set.Name, msg)
+	}
+	return placeholderRegex.ReplaceAllStringFunc(msg, func(placeholder string) string {
+		switch name := placeholder[1 : len(placeholder)-1]; name {
+		case "label":
+			return set.FieldLabel
+		case "field":
+			return key
+		case "value":
+			return fmt.Sprint(set.FieldValue)
+		case "param", set.Name:
+			return formatParam(set.RuleValue)
+		default:
+			return placeholder
+		}
+	})
+}
+
+func formatParam(v any) string {
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Slice {
+		params := make([]string, val.Len())
+		for i := range params {
+			params[i] = fmt.Sprint(val.Index(i).Interface())
+		}
+		return strings.Join(params, ", ")
+	}
+	return fmt.Sprint(v)
+}
+
+// fieldLabel returns the label tag of the field, or its name in words,
+// like "First name" for FirstName.
+func fieldLabel(parent reflect.Value, name string) string {
+	if parent.IsValid() {
+		if field, ok := parent.Type().FieldByName(name); ok {
+			if label := field.Tag.Get("label"); len(label) > 0 {
+				return label
+			}
+		}
+	}
+	return humanize(name)
+}
+
+func humanize(name string) string {
+	if len(name) == 0 || isUppercase(name) {
+		return name
+	}
+	// Split the name into words at every uppercase letter, keeping
+	// acronyms like the ID of UserID together.
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if !unicode.IsUpper(runes[i-1]) || nextLower {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(runes[start:]))
+	for i, word := range words {
+		if !isUppercase(word) {
+			word = strings.ToLower(word)
+		}
+		if i == 0 {
+			r, size := utf8.DecodeRuneInString(word)
+			word = string(unicode.ToUpper(r)) + word[size:]
+		}
+		words[i] = word
+	}
+	return strings.Join(words, " ")
+}
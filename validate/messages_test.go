@@ -0,0 +1,75 @@
+package validate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessagePlaceholders(t *testing.T) {
+	type Signup struct {
+		FirstName string
+		Country   string `label:"Country of residence"`
+		Plan      string
+	}
+	schema := Schema{
+		"firstName": Rules(Min(2).Message("{label} must be at least {min} characters")),
+		"country":   Rules(Required.Message("{label} is required ({field})")),
+		"plan":      Rules(In([]string{"free", "pro"}).Message("{value} is not one of {param}")),
+	}
+	errors, ok := Validate(Signup{FirstName: "A", Plan: "gold"}, schema)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"First name must be at least 2 characters"}, errors["firstName"])
+	assert.Equal(t, []string{"Country of residence is required (country)"}, errors["country"])
+	assert.Equal(t, []string{"gold is not one of free, pro"}, errors["plan"])
+}
+
+func TestSetMessageAndTranslator(t *testing.T) {
+	t.Cleanup(func() {
+		messagemu.Lock()
+		messages = map[string]string{}
+		translator = nil
+		messagemu.Unlock()
+	})
+	type Signup struct {
+		FirstName string
+		LastName  string
+	}
+	schema := Schema{
+		"firstName": Rules(Min(2)),
+		"lastName":  Rules(Min(2).Message("too short")),
+	}
+	SetMessage("min", "{label} must be at least {min} characters")
+	errors, _ := Validate(Signup{FirstName: "A", LastName: "B"}, schema)
+	assert.Equal(t, []string{"First name must be at least 2 characters"}, errors["firstName"])
+	// The message of the RuleSet itself wins over the global one.
+	assert.Equal(t, []string{"too short"}, errors["lastName"])
+
+	type localeKey struct{}
+	SetTranslator(func(ctx context.Context, rule, msg string) string {
+		if ctx.Value(localeKey{}) == "nl" && rule == "min" {
+			return "{label} moet minstens {min} tekens lang zijn"
+		}
+		return strings.ToUpper(msg)
+	})
+	ctx := context.WithValue(context.Background(), localeKey{}, "nl")
+	errors, _ = ValidateContext(ctx, Signup{FirstName: "A", LastName: "B"}, schema)
+	assert.Equal(t, []string{"First name moet minstens 2 tekens lang zijn"}, errors["firstName"])
+	errors, _ = Validate(Signup{FirstName: "A", LastName: "B"}, schema)
+	assert.Equal(t, []string{"TOO SHORT"}, errors["lastName"])
+}
+
+func TestHumanize(t *testing.T) {
+	for name, label := range map[string]string{
+		"firstName":       "First name",
+		"PasswordConfirm": "Password confirm",
+		"UserID":          "User ID",
+		"URL":             "URL",
+		"HTMLTitle":       "HTML title",
+		"email":           "Email",
+	} {
+		assert.Equal(t, label, humanize(name), name)
+	}
+}
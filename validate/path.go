@@ -0,0 +1,169 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A path addresses a field of a Schema, like "email", "address.city",
+// "items[].quantity" for the quantity of every item, "items[0].quantity"
+// for the first item only, or "prices[eur]" for a map entry.
+type pathToken struct {
+	// field is the name of a struct field, empty for an index token.
+	field string
+	// index is the slice index or map key, empty for every element.
+	index string
+}
+
+func parsePath(path string) ([]pathToken, error) {
+	var tokens []pathToken
+	for _, part := range strings.Split(path, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		if len(name) == 0 {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+		tokens = append(tokens, pathToken{field: name})
+		for len(rest) > 0 {
+			index, after, ok := strings.Cut(rest, "]")
+			if !ok {
+				return nil, fmt.Errorf("invalid path %q", path)
+			}
+			tokens = append(tokens, pathToken{index: index})
+			rest = strings.TrimPrefix(after, "[")
+		}
+	}
+	return tokens, nil
+}
+
+// structFieldName returns the name of the struct field for a field name
+// of a path. The first letter is uppercased so we never check un-exported
+// fields. But we need to watch out for member fields that are uppercased
+// by the user. For example (URL, ID, ...)
+func structFieldName(name string) string {
+	if isUppercase(name) {
+		return name
+	}
+	return string(unicode.ToUpper(rune(name[0]))) + name[1:]
+}
+
+// errorFieldName returns the name of a field as used in the keys of Errors.
+func errorFieldName(name string) string {
+	return string(unicode.ToLower([]rune(name)[0])) + name[1:]
+}
+
+// normalizePath spells every field of the path like its struct field, so
+// "address.city" and "Address.City" are the same key of a Schema.
+func normalizePath(path string) string {
+	tokens, err := parsePath(path)
+	if err != nil {
+		return path
+	}
+	var b strings.Builder
+	for _, tok := range tokens {
+		if len(tok.field) == 0 {
+			b.WriteString("[" + tok.index + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(structFieldName(tok.field))
+	}
+	return b.String()
+}
+
+// target is a value addressed by a path, with its key in Errors, the
+// name of the struct field it belongs to and the struct holding that field.
+type target struct {
+	key    string
+	field  string
+	parent reflect.Value
+	value  reflect.Value
+}
+
+// resolve returns the values addressed by the path tokens. A missing field
+// resolves to an invalid value, like a nil FieldValue, while nothing below
+// a nil pointer is resolved.
+func resolve(v reflect.Value, t target, tokens []pathToken) []target {
+	if len(tokens) == 0 {
+		t.value = v
+		return []target{t}
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	tok := tokens[0]
+	if len(tok.field) > 0 {
+		name := structFieldName(tok.field)
+		t.field = name
+		t.parent = v
+		if len(t.key) > 0 {
+			t.key += "."
+		}
+		t.key += errorFieldName(name)
+		var next reflect.Value
+		if v.Kind() == reflect.Struct {
+			next = v.FieldByName(name)
+		} else {
+			t.parent = reflect.Value{}
+		}
+		if !next.IsValid() || !next.CanInterface() {
+			return []target{t}
+		}
+		return resolve(next, t, tokens[1:])
+	}
+	var targets []target
+	for _, el := range elements(v, t) {
+		if len(tok.index) == 0 || tok.index == el.index {
+			targets = append(targets, resolve(el.value, el.target, tokens[1:])...)
+		}
+	}
+	return targets
+}
+
+type element struct {
+	target
+	index string
+}
+
+// elements returns the elements of a slice, array or map, keyed by their
+// index. Map entries are sorted by key to report errors in a stable order.
+func elements(v reflect.Value, parent target) []element {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	var elems []element
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			index := strconv.Itoa(i)
+			elems = append(elems, element{
+				target: target{key: parent.key + "[" + index + "]", field: parent.field, parent: parent.parent, value: v.Index(i)},
+				index:  index,
+			})
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+		for _, key := range keys {
+			index := fmt.Sprint(key.Interface())
+			elems = append(elems, element{
+				target: target{key: parent.key + "[" + index + "]", field: parent.field, parent: parent.parent, value: v.MapIndex(key)},
+				index:  index,
+			})
+		}
+	}
+	return elems
+}
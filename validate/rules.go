@@ -1,11 +1,13 @@
 package validate
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
 	"time"
 	"unicode"
+	"unicode/utf8"
 )
 
 var (
@@ -15,16 +17,47 @@ var (
 
 // RuleSet holds the state of a single rule.
 type RuleSet struct {
-	Name         string
-	RuleValue    any
-	FieldValue   any
-	FieldName    any
+	Name       string
+	RuleValue  any
+	FieldValue any
+	FieldName  any
+	// FieldLabel is the display name of the field, its label tag or its
+	// name in words, see SetMessage.
+	FieldLabel   string
 	ErrorMessage string
 	MessageFunc  func(RuleSet) string
 	ValidateFunc func(RuleSet) bool
+	// ValidateContextFunc is used instead of ValidateFunc by rules that
+	// need a context, like the ones querying a database. A returned error
+	// fails the validation.
+	ValidateContextFunc func(context.Context, RuleSet) (bool, error)
+
+	// each holds the rules of Each.
+	each []RuleSet
+	// parent is the struct holding the field, see Field.
+	parent reflect.Value
+	// required rules also validate absent values, like nil pointers,
+	// other rules skip them.
+	required bool
+}
+
+// Field returns the value of another field of the struct holding the
+// field being validated, which allows rules to compare fields. It returns
+// nil if there is no such field.
+func (set RuleSet) Field(name string) any {
+	if !set.parent.IsValid() || len(name) == 0 {
+		return nil
+	}
+	field := set.parent.FieldByName(structFieldName(name))
+	if !field.IsValid() || !field.CanInterface() {
+		return nil
+	}
+	v, _ := fieldValue(field)
+	return v
 }
 
-// Message overrides the default message of a RuleSet
+// Message overrides the default message of a RuleSet. The message may
+// contain placeholders, see SetMessage.
 func (set RuleSet) Message(msg string) RuleSet {
 	set.ErrorMessage = msg
 	return set
@@ -53,6 +86,21 @@ func In[T any](values []T) RuleSet {
 	}
 }
 
+// Each applies the given rules to every element of a slice, array or map
+// instead of to the field itself. Errors are keyed by the path of the
+// element, like "tags[2]".
+//
+//	"tags": Rules(Each(Min(2), Max(20))),
+func Each(rules ...RuleSet) RuleSet {
+	return RuleSet{
+		Name:         "each",
+		RuleValue:    rules,
+		each:         rules,
+		ValidateFunc: func(RuleSet) bool { return true },
+		MessageFunc:  func(RuleSet) string { return "" },
+	}
+}
+
 var ContainsUpper = RuleSet{
 	Name: "containsUpper",
 	ValidateFunc: func(rule RuleSet) bool {
@@ -100,17 +148,16 @@ var ContainsSpecial = RuleSet{
 	},
 }
 
+// Required validates that the field is not empty, that is not the zero
+// value of its type, nor a nil pointer or an invalid sql.Null.
 var Required = RuleSet{
-	Name: "required",
+	Name:     "required",
+	required: true,
 	MessageFunc: func(set RuleSet) string {
 		return "is a required field"
 	},
 	ValidateFunc: func(rule RuleSet) bool {
-		str, ok := rule.FieldValue.(string)
-		if !ok {
-			return false
-		}
-		return len(str) > 0
+		return isPresent(rule.FieldValue)
 	},
 }
 
@@ -160,14 +207,14 @@ func TimeAfter(t time.Time) RuleSet {
 	return RuleSet{
 		Name: "timeAfter",
 		ValidateFunc: func(set RuleSet) bool {
-			t, ok := set.FieldValue.(time.Time)
+			v, ok := set.FieldValue.(time.Time)
 			if !ok {
 				return false
 			}
-			return t.After(t)
+			return v.After(t)
 		},
 		MessageFunc: func(set RuleSet) string {
-			return fmt.Sprintf("is not after %v", set.FieldValue)
+			return fmt.Sprintf("is not after %v", t)
 		},
 	}
 }
@@ -176,14 +223,14 @@ func TimeBefore(t time.Time) RuleSet {
 	return RuleSet{
 		Name: "timeBefore",
 		ValidateFunc: func(set RuleSet) bool {
-			t, ok := set.FieldValue.(time.Time)
+			v, ok := set.FieldValue.(time.Time)
 			if !ok {
 				return false
 			}
-			return t.Before(t)
+			return v.Before(t)
 		},
 		MessageFunc: func(set RuleSet) string {
-			return fmt.Sprintf("is not before %v", set.FieldValue)
+			return fmt.Sprintf("is not before %v", t)
 		},
 	}
 }
@@ -253,6 +300,8 @@ func GT[T Numeric](n T) RuleSet {
 	}
 }
 
+// Max validates that the string is at most n characters long, counting
+// runes rather than bytes.
 func Max(n int) RuleSet {
 	return RuleSet{
 		Name:      "max",
@@ -262,7 +311,7 @@ func Max(n int) RuleSet {
 			if !ok {
 				return false
 			}
-			return len(str) <= n
+			return utf8.RuneCountInString(str) <= n
 		},
 		MessageFunc: func(set RuleSet) string {
 			return fmt.Sprintf("should be maximum %d characters long", n)
@@ -270,6 +319,8 @@ func Max(n int) RuleSet {
 	}
 }
 
+// Min validates that the string is at least n characters long, counting
+// runes rather than bytes.
 func Min(n int) RuleSet {
 	return RuleSet{
 		Name:      "min",
@@ -279,7 +330,7 @@ func Min(n int) RuleSet {
 			if !ok {
 				return false
 			}
-			return len(str) >= n
+			return utf8.RuneCountInString(str) >= n
 		},
 		MessageFunc: func(set RuleSet) string {
 			return fmt.Sprintf("should be at least %d characters long", n)
@@ -287,6 +338,112 @@ func Min(n int) RuleSet {
 	}
 }
 
+// EqualField validates that the field equals the given other field, like
+// a password confirmation.
+func EqualField(field string) RuleSet {
+	return RuleSet{
+		Name:      "equalField",
+		RuleValue: field,
+		ValidateFunc: func(set RuleSet) bool {
+			return reflect.DeepEqual(set.FieldValue, set.Field(field))
+		},
+		MessageFunc: func(set RuleSet) string {
+			return fmt.Sprintf("should be equal to %s", field)
+		},
+	}
+}
+
+// RequiredIf validates that the field is not empty when the given other
+// field equals value.
+//
+//	"company": Rules(RequiredIf("accountType", "business")),
+func RequiredIf(field string, value any) RuleSet {
+	return requiredWhen("requiredIf", []any{field, value}, func(set RuleSet) bool {
+		return reflect.DeepEqual(set.Field(field), value)
+	})
+}
+
+// RequiredWith validates that the field is not empty when any of the
+// given other fields is not empty.
+func RequiredWith(fields ...string) RuleSet {
+	return requiredWhen("requiredWith", fields, func(set RuleSet) bool {
+		for _, field := range fields {
+			if isPresent(set.Field(field)) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// RequiredWithout validates that the field is not empty when any of the
+// given other fields is empty.
+func RequiredWithout(fields ...string) RuleSet {
+	return requiredWhen("requiredWithout", fields, func(set RuleSet) bool {
+		for _, field := range fields {
+			if !isPresent(set.Field(field)) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func requiredWhen(name string, value any, required func(RuleSet) bool) RuleSet {
+	return RuleSet{
+		Name:      name,
+		RuleValue: value,
+		required:  true,
+		ValidateFunc: func(set RuleSet) bool {
+			return !required(set) || isPresent(set.FieldValue)
+		},
+		MessageFunc: func(set RuleSet) string {
+			return "is a required field"
+		},
+	}
+}
+
+// AfterField validates that the time is after the time of the given
+// other field.
+//
+//	"endDate": Rules(AfterField("startDate")),
+func AfterField(field string) RuleSet {
+	return RuleSet{
+		Name:      "afterField",
+		RuleValue: field,
+		ValidateFunc: func(set RuleSet) bool {
+			v, ok := set.FieldValue.(time.Time)
+			other, otherOK := set.Field(field).(time.Time)
+			return ok && otherOK && v.After(other)
+		},
+		MessageFunc: func(set RuleSet) string {
+			return fmt.Sprintf("should be after %s", field)
+		},
+	}
+}
+
+// BeforeField validates that the time is before the time of the given
+// other field.
+func BeforeField(field string) RuleSet {
+	return RuleSet{
+		Name:      "beforeField",
+		RuleValue: field,
+		ValidateFunc: func(set RuleSet) bool {
+			v, ok := set.FieldValue.(time.Time)
+			other, otherOK := set.Field(field).(time.Time)
+			return ok && otherOK && v.Before(other)
+		},
+		MessageFunc: func(set RuleSet) string {
+			return fmt.Sprintf("should be before %s", field)
+		},
+	}
+}
+
+// isPresent reports whether v is set, that is not nil nor a zero value.
+func isPresent(v any) bool {
+	return v != nil && !reflect.ValueOf(v).IsZero()
+}
+
 func hasDigit(s string) bool {
 	for _, char := range s {
 		if unicode.IsDigit(char) {
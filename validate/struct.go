@@ -0,0 +1,390 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tagRule builds the RuleSet of a rule in a validate tag, for a field of
+// the given type and with the parameter after the "=", if any.
+type tagRule func(typ reflect.Type, param string) (RuleSet, error)
+
+var tagRules = map[string]tagRule{
+	"required":        constRule(Required),
+	"email":           constRule(Email),
+	"url":             constRule(URL),
+	"time":            constRule(Time),
+	"containsUpper":   constRule(ContainsUpper),
+	"containsDigit":   constRule(ContainsDigit),
+	"containsSpecial": constRule(ContainsSpecial),
+	"uuid":            constRule(UUID),
+	"ipv4":            constRule(IPv4),
+	"ipv6":            constRule(IPv6),
+	"cidr":            constRule(CIDR),
+	"alpha":           constRule(Alpha),
+	"alphanumeric":    constRule(Alphanumeric),
+	"ascii":           constRule(ASCII),
+	"json":            constRule(JSON),
+	"base64":          constRule(Base64),
+	"hexColor":        constRule(HexColor),
+	"slug":            constRule(Slug),
+	"phone":           constRule(Phone),
+	"creditCard":      constRule(CreditCard),
+	"iban":            constRule(IBAN),
+	"semver":          constRule(Semver),
+	"country":         constRule(Country),
+	"currency":        constRule(Currency),
+	"min": func(_ reflect.Type, param string) (RuleSet, error) {
+		n, err := strconv.Atoi(param)
+		return Min(n), err
+	},
+	"max": func(_ reflect.Type, param string) (RuleSet, error) {
+		n, err := strconv.Atoi(param)
+		return Max(n), err
+	},
+	"gte": numericRule(GTE[int], GTE[float64]),
+	"gt":  numericRule(GT[int], GT[float64]),
+	"lte": numericRule(LTE[int], LTE[float64]),
+	"lt":  numericRule(LT[int], LT[float64]),
+	"eq": func(typ reflect.Type, param string) (RuleSet, error) {
+		switch typ.Kind() {
+		case reflect.String:
+			return EQ(param), nil
+		case reflect.Bool:
+			b, err := strconv.ParseBool(param)
+			return EQ(b), err
+		}
+		return numericRule(EQ[int], EQ[float64])(typ, param)
+	},
+	"in":    listRule(In[string], In[int], In[float64]),
+	"notIn": listRule(NotIn[string], NotIn[int], NotIn[float64]),
+	// The bounds of between are separated by "|": between=1|10
+	"between": func(typ reflect.Type, param string) (RuleSet, error) {
+		lo, hi, ok := strings.Cut(param, "|")
+		if !ok {
+			return RuleSet{}, fmt.Errorf("expected a minimum and a maximum")
+		}
+		if typ.Kind() == reflect.Int {
+			min, err := strconv.Atoi(lo)
+			if err != nil {
+				return RuleSet{}, err
+			}
+			max, err := strconv.Atoi(hi)
+			return Between(min, max), err
+		}
+		if !isNumeric(typ) {
+			return RuleSet{}, fmt.Errorf("unsupported type %s", typ)
+		}
+		min, err := strconv.ParseFloat(lo, 64)
+		if err != nil {
+			return RuleSet{}, err
+		}
+		max, err := strconv.ParseFloat(hi, 64)
+		return floatRule(typ, Between(min, max)), err
+	},
+	// The expression of match can't contain commas, use Match in a Schema
+	// for those.
+	"match": func(_ reflect.Type, param string) (RuleSet, error) {
+		re, err := regexp.Compile(param)
+		if err != nil {
+			return RuleSet{}, err
+		}
+		return Match(re), nil
+	},
+	"startsWith": func(_ reflect.Type, param string) (RuleSet, error) {
+		return StartsWith(param), nil
+	},
+	"endsWith": func(_ reflect.Type, param string) (RuleSet, error) {
+		return EndsWith(param), nil
+	},
+	"date": func(_ reflect.Type, param string) (RuleSet, error) {
+		if len(param) == 0 {
+			param = time.DateOnly
+		}
+		return Date(param), nil
+	},
+	"equalField": func(_ reflect.Type, param string) (RuleSet, error) {
+		return EqualField(param), nil
+	},
+	// The value of requiredIf follows the field name after a space and is
+	// compared to the other field in its string form: requiredIf=plan pro
+	"requiredIf": func(_ reflect.Type, param string) (RuleSet, error) {
+		field, value, ok := strings.Cut(param, " ")
+		if !ok {
+			return RuleSet{}, fmt.Errorf("expected a field and a value")
+		}
+		return requiredWhen("requiredIf", []any{field, value}, func(set RuleSet) bool {
+			return fmt.Sprint(set.Field(field)) == value
+		}), nil
+	},
+	"requiredWith": func(_ reflect.Type, param string) (RuleSet, error) {
+		return RequiredWith(strings.Split(param, "|")...), nil
+	},
+	"requiredWithout": func(_ reflect.Type, param string) (RuleSet, error) {
+		return RequiredWithout(strings.Split(param, "|")...), nil
+	},
+	"afterField": func(_ reflect.Type, param string) (RuleSet, error) {
+		return AfterField(param), nil
+	},
+	"beforeField": func(_ reflect.Type, param string) (RuleSet, error) {
+		return BeforeField(param), nil
+	},
+	"timeAfter": func(_ reflect.Type, param string) (RuleSet, error) {
+		t, err := parseTagTime(param)
+		return TimeAfter(t), err
+	},
+	"timeBefore": func(_ reflect.Type, param string) (RuleSet, error) {
+		t, err := parseTagTime(param)
+		return TimeBefore(t), err
+	},
+}
+
+func constRule(set RuleSet) tagRule {
+	return func(reflect.Type, string) (RuleSet, error) {
+		return set, nil
+	}
+}
+
+// numericRule picks the int or float64 variant of a generic rule,
+// depending on the type of the field. Fields of other numeric types, like
+// the int64 of a sql.NullInt64, are compared as float64.
+func numericRule(ints func(int) RuleSet, floats func(float64) RuleSet) tagRule {
+	return func(typ reflect.Type, param string) (RuleSet, error) {
+		switch typ.Kind() {
+		case reflect.Int:
+			n, err := strconv.Atoi(param)
+			return ints(n), err
+		case reflect.Float64:
+			f, err := strconv.ParseFloat(param, 64)
+			return floats(f), err
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32:
+			f, err := strconv.ParseFloat(param, 64)
+			return floatRule(typ, floats(f)), err
+		}
+		return RuleSet{}, fmt.Errorf("unsupported type %s", typ)
+	}
+}
+
+// listRule picks the string, int or float64 variant of a generic rule
+// taking the values of its parameter, separated by "|".
+func listRule(strs func([]string) RuleSet, ints func([]int) RuleSet, floats func([]float64) RuleSet) tagRule {
+	return func(typ reflect.Type, param string) (RuleSet, error) {
+		values := strings.Split(param, "|")
+		switch typ.Kind() {
+		case reflect.String:
+			return strs(values), nil
+		case reflect.Int:
+			n := make([]int, len(values))
+			for i, v := range values {
+				var err error
+				if n[i], err = strconv.Atoi(v); err != nil {
+					return RuleSet{}, err
+				}
+			}
+			return ints(n), nil
+		case reflect.Float64:
+			f := make([]float64, len(values))
+			for i, v := range values {
+				var err error
+				if f[i], err = strconv.ParseFloat(v, 64); err != nil {
+					return RuleSet{}, err
+				}
+			}
+			return floats(f), nil
+		}
+		return RuleSet{}, fmt.Errorf("unsupported type %s", typ)
+	}
+}
+
+// floatRule makes a float64 rule validate fields of the given numeric
+// type by converting their value first.
+func floatRule(typ reflect.Type, set RuleSet) RuleSet {
+	if typ.Kind() == reflect.Float64 {
+		return set
+	}
+	validate := set.ValidateFunc
+	set.ValidateFunc = func(set RuleSet) bool {
+		set.FieldValue = toFloat(set.FieldValue)
+		return validate(set)
+	}
+	return set
+}
+
+func isNumeric(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func toFloat(v any) any {
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint())
+	case reflect.Float32, reflect.Float64:
+		return val.Float()
+	}
+	return v
+}
+
+func parseTagTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+var structSchemas sync.Map // map[reflect.Type]Schema
+
+// Struct validates v, a struct or a pointer to one, based on the rules in
+// the validate tags of its fields, combined with the rules of the given
+// schemas. Rules are separated by commas and their parameter follows a
+// "=", the values of "in" are separated by "|".
+//
+//	type SignupFormValues struct {
+//		Email    string `form:"email" validate:"required,email"`
+//		Password string `form:"password" validate:"required,containsUpper,min=7,max=50"`
+//		Currency string `form:"currency" validate:"in=eur|usd"`
+//	}
+//
+//	errors, ok := validate.Struct(&values)
+//
+// The rules are parsed once per type. Struct panics on invalid tags,
+// like an unknown rule or a parameter that doesn't fit the field type.
+func Struct(v any, schemas ...Schema) (Errors, bool) {
+	return StructContext(context.Background(), v, schemas...)
+}
+
+// StructContext is like Struct, but passes ctx to the rules, see
+// ValidateContext.
+func StructContext(ctx context.Context, v any, schemas ...Schema) (Errors, bool) {
+	return ValidateContext(ctx, v, combine(structSchema(reflect.TypeOf(v)), schemas...))
+}
+
+// structSchema returns the Schema built from the validate tags of the
+// given struct type. The tags of nested structs, and of the structs in
+// slices, arrays and maps, are included with the path of the field.
+func structSchema(typ reflect.Type) Schema {
+	return buildStructSchema(typ, map[reflect.Type]bool{})
+}
+
+func buildStructSchema(typ reflect.Type, visiting map[reflect.Type]bool) Schema {
+	if typ == nil {
+		return Schema{}
+	}
+	typ = indirect(typ)
+	if typ.Kind() != reflect.Struct {
+		return Schema{}
+	}
+	if schema, ok := structSchemas.Load(typ); ok {
+		return schema.(Schema)
+	}
+	// Fields of the type being built, of a recursive type, are skipped.
+	if visiting[typ] {
+		return Schema{}
+	}
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	schema := Schema{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		if len(tag) > 0 {
+			ruleSets, err := parseTag(field.Type, tag)
+			if err != nil {
+				panic(fmt.Sprintf("validate: invalid tag on field %s.%s: %v", typ.Name(), field.Name, err))
+			}
+			schema[field.Name] = ruleSets
+		}
+		prefix, elem := field.Name, indirect(field.Type)
+		switch elem.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			prefix, elem = prefix+"[]", indirect(elem.Elem())
+		}
+		if elem.Kind() != reflect.Struct || elem == reflect.TypeOf(time.Time{}) {
+			continue
+		}
+		for path, ruleSets := range buildStructSchema(elem, visiting) {
+			schema[prefix+"."+path] = ruleSets
+		}
+	}
+	if len(visiting) == 1 {
+		structSchemas.Store(typ, schema)
+	}
+	return schema
+}
+
+func indirect(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+func parseTag(typ reflect.Type, tag string) ([]RuleSet, error) {
+	var ruleSets []RuleSet
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if len(rule) == 0 {
+			continue
+		}
+		name, param, _ := strings.Cut(rule, "=")
+		if name == "each" {
+			elem := indirect(typ)
+			if k := elem.Kind(); k != reflect.Slice && k != reflect.Array && k != reflect.Map {
+				return nil, fmt.Errorf("rule \"each\": unsupported type %s", typ)
+			}
+			each, err := parseTag(elem.Elem(), strings.Join(rules[i+1:], ","))
+			if err != nil {
+				return nil, err
+			}
+			return append(ruleSets, Each(each...)), nil
+		}
+		build, ok := tagRules[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown rule %q", name)
+		}
+		set, err := build(valueType(typ), param)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %v", name, err)
+		}
+		ruleSets = append(ruleSets, set)
+	}
+	return ruleSets, nil
+}
+
+// combine returns a new Schema holding the rules of all the given schemas.
+// Unlike Merge, the rules of a field defined in several schemas are
+// appended to each other.
+func combine(schema Schema, others ...Schema) Schema {
+	combined := Schema{}
+	for _, s := range append([]Schema{schema}, others...) {
+		for fieldName, ruleSets := range s {
+			// Schemas may spell a field name in lowercase, like the keys
+			// of Errors, while tags are keyed by the struct field name.
+			fieldName = normalizePath(fieldName)
+			combined[fieldName] = slices.Concat(combined[fieldName], ruleSets)
+		}
+	}
+	return combined
+}
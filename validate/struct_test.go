@@ -0,0 +1,164 @@
+package validate
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tagged struct {
+	Email    string    `validate:"required,email"`
+	Password string    `validate:"containsUpper,containsDigit,min=7,max=50"`
+	Age      int       `validate:"gte=18,lt=130"`
+	Rating   float64   `validate:"gt=0,lte=5"`
+	Currency string    `validate:"in=eur|usd"`
+	Plan     int       `validate:"in=1|2|3"`
+	Country  string    `validate:"eq=BE"`
+	StartsAt time.Time `validate:"timeAfter=2024-01-01"`
+	Notes    string
+	internal string `validate:"required"`
+}
+
+func TestStruct(t *testing.T) {
+	v := tagged{
+		Email:    "foo@bar.com",
+		Password: "Hunter123",
+		Age:      30,
+		Rating:   4.5,
+		Currency: "eur",
+		Plan:     2,
+		Country:  "BE",
+		StartsAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+	errors, ok := Struct(&v)
+	assert.True(t, ok)
+	assert.Empty(t, errors)
+
+	v = tagged{
+		Email:    "foo",
+		Password: "hunter",
+		Age:      12,
+		Rating:   0,
+		Currency: "gbp",
+		Plan:     4,
+		Country:  "NL",
+		StartsAt: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+	errors, ok = Struct(v)
+	assert.False(t, ok)
+	assert.Len(t, errors["email"], 1)
+	assert.Len(t, errors["password"], 3)
+	assert.Len(t, errors["age"], 1)
+	assert.Len(t, errors["rating"], 1)
+	assert.Len(t, errors["currency"], 1)
+	assert.Len(t, errors["plan"], 1)
+	assert.Len(t, errors["country"], 1)
+	assert.Len(t, errors["startsAt"], 1)
+	assert.False(t, errors.Has("notes"))
+	assert.False(t, errors.Has("internal"))
+}
+
+func TestStructWithSchema(t *testing.T) {
+	v := tagged{
+		Email:    "foo@bar.com",
+		Password: "Hunter123",
+		Age:      30,
+		Rating:   1,
+		Currency: "usd",
+		Plan:     1,
+		Country:  "BE",
+		StartsAt: time.Now(),
+		Notes:    "hi",
+	}
+	// Rules of the schema are added to the rules of the tags.
+	errors, ok := Struct(v, Schema{
+		"notes":    Rules(Min(3)),
+		"password": Rules(ContainsSpecial),
+	})
+	assert.False(t, ok)
+	assert.Len(t, errors["notes"], 1)
+	assert.Len(t, errors["password"], 1)
+}
+
+func TestStructInvalidTag(t *testing.T) {
+	type invalid struct {
+		Name string `validate:"required,foo"`
+	}
+	assert.Panics(t, func() { Struct(invalid{}) })
+
+	type mismatch struct {
+		Name string `validate:"gte=3"`
+	}
+	assert.Panics(t, func() { Struct(mismatch{}) })
+}
+
+func TestRequestStructTags(t *testing.T) {
+	type SignupData struct {
+		Email     string `form:"email" validate:"email"`
+		FirstName string `form:"firstName" validate:"min=2,max=50"`
+	}
+	formValues := url.Values{}
+	formValues.Set("email", "foo")
+	formValues.Set("firstName", "A")
+	req, err := http.NewRequest("POST", "http://foo.com", strings.NewReader(formValues.Encode()))
+	assert.Nil(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var data SignupData
+	errors, ok := Request(req, &data, nil)
+	assert.False(t, ok)
+	assert.Len(t, errors["email"], 1)
+	assert.Len(t, errors["firstName"], 1)
+}
+
+func TestStructNested(t *testing.T) {
+	type LineItem struct {
+		Description string `validate:"min=3"`
+		Quantity    int    `validate:"gte=1"`
+	}
+	type Address struct {
+		City string `validate:"required"`
+	}
+	type Invoice struct {
+		Address  *Address
+		Items    []LineItem
+		Tags     []string `validate:"each,min=2,max=10"`
+		Children []*Invoice
+	}
+	invoice := Invoice{
+		Address: &Address{},
+		Items: []LineItem{
+			{Description: "Consulting", Quantity: 2},
+			{Description: "x", Quantity: 0},
+		},
+		Tags:     []string{"ok", "x"},
+		Children: []*Invoice{{Address: &Address{City: "Ghent"}}},
+	}
+	errors, ok := Struct(&invoice)
+	assert.False(t, ok)
+	assert.Len(t, errors, 4)
+	assert.True(t, errors.Has("address.city"))
+	assert.True(t, errors.Has("items[1].description"))
+	assert.True(t, errors.Has("items[1].quantity"))
+	assert.True(t, errors.Has("tags[1]"))
+}
+
+func TestStructCrossFieldTags(t *testing.T) {
+	type Signup struct {
+		Plan            string
+		VatNumber       string `validate:"requiredIf=plan business"`
+		Password        string
+		PasswordConfirm string `validate:"equalField=password"`
+	}
+	errors, ok := Struct(Signup{Plan: "business", Password: "a", PasswordConfirm: "b"})
+	assert.False(t, ok)
+	assert.True(t, errors.Has("vatNumber"))
+	assert.True(t, errors.Has("passwordConfirm"))
+
+	_, ok = Struct(Signup{Plan: "free", Password: "a", PasswordConfirm: "a"})
+	assert.True(t, ok)
+}
@@ -1,11 +1,10 @@
 package validate
 
 import (
-	"fmt"
+	"context"
 	"maps"
 	"net/http"
 	"reflect"
-	"strconv"
 	"unicode"
 )
 
@@ -58,126 +57,200 @@ func Rules(rules ...RuleSet) []RuleSet {
 
 // Validate validates data based on the given Schema.
 func Validate(data any, fields Schema) (Errors, bool) {
+	return ValidateContext(context.Background(), data, fields)
+}
+
+// ValidateContext validates data based on the given Schema, passing ctx to
+// the rules that need one, like Unique. Errors of such rules, like a failed
+// query, are added to the "_error" key, as are malformed paths of the
+// schema.
+//
+//	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+//	defer cancel()
+//	errors, ok := validate.ValidateContext(ctx, values, schema)
+func ValidateContext(ctx context.Context, data any, fields Schema) (Errors, bool) {
 	errors := Errors{}
-	return validate(data, fields, errors)
+	return validate(ctx, data, fields, errors)
 }
 
 // Request parses an http.Request into data and validates it based
-// on the given schema.
+// on the validate tags of data, see Struct, and the given schema,
+// which may be nil. The rules are passed the context of the request.
 func Request(r *http.Request, data any, schema Schema) (Errors, bool) {
+	return RequestContext(r.Context(), r, data, schema)
+}
+
+// RequestContext is like Request, but passes ctx to the rules, see
+// ValidateContext.
+func RequestContext(ctx context.Context, r *http.Request, data any, schema Schema) (Errors, bool) {
 	errors := Errors{}
-	if err := parseRequest(r, data); err != nil {
-		errors["_error"] = []string{err.Error()}
+	parseErr := parseRequest(r, data)
+	if parseErr != nil {
+		errors["_error"] = []string{parseErr.Error()}
 	}
-	return validate(data, schema, errors)
+	errors, ok := validate(ctx, data, combine(structSchema(reflect.TypeOf(data)), schema), errors)
+	return errors, ok && parseErr == nil
 }
 
-func validate(data any, schema Schema, errors Errors) (Errors, bool) {
+// Validator is implemented by types with rules that involve more than
+// their fields, which are checked after the rules of the fields. The keys
+// of the returned Errors are relative to the type, they are prefixed with
+// the path of nested types.
+//
+//	func (v SignupFormValues) Validate() validate.Errors {
+//		errors := validate.Errors{}
+//		if v.Plan == "business" && !strings.HasSuffix(v.Email, "@"+v.CompanyDomain) {
+//			errors.Add("email", "must be a company address")
+//		}
+//		return errors
+//	}
+type Validator interface {
+	Validate() Errors
+}
+
+func validate(ctx context.Context, data any, schema Schema, errors Errors) (Errors, bool) {
 	ok := true
-	for fieldName, ruleSets := range schema {
-		// Uppercase the field name so we never check un-exported fields.
-		// But we need to watch out for member fields that are uppercased by
-		// the user. For example (URL, ID, ...)
-		if !isUppercase(fieldName) {
-			fieldName = string(unicode.ToUpper(rune(fieldName[0]))) + fieldName[1:]
-		}
-
-		fieldValue := getFieldAndTagByName(data, fieldName)
-		for _, set := range ruleSets {
-			set.FieldValue = fieldValue
-			set.FieldName = fieldName
-			fieldName = string(unicode.ToLower([]rune(fieldName)[0])) + fieldName[1:]
-			if !set.ValidateFunc(set) {
+	root := reflect.ValueOf(data)
+	for path, ruleSets := range schema {
+		tokens, err := parsePath(path)
+		if err != nil {
+			// A malformed key fails the validation rather than the request.
+			errors.Add("_error", err.Error())
+			ok = false
+			continue
+		}
+		for _, t := range resolve(root, target{}, tokens) {
+			if !validateTarget(ctx, t, ruleSets, errors) {
 				ok = false
-				msg := set.MessageFunc(set)
-				if len(set.ErrorMessage) > 0 {
-					msg = set.ErrorMessage
-				}
-				if _, ok := errors[fieldName]; !ok {
-					errors[fieldName] = []string{}
-				}
-				errors[fieldName] = append(errors[fieldName], msg)
 			}
 		}
 	}
+	if !runValidators(root, "", errors, map[uintptr]bool{}) {
+		ok = false
+	}
 	return errors, ok
 }
 
-func getFieldAndTagByName(v any, name string) any {
-	val := reflect.ValueOf(v)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
+// runValidators calls the Validators of v and the values nested in it.
+func runValidators(v reflect.Value, key string, errors Errors, seen map[uintptr]bool) bool {
+	if !v.IsValid() {
+		return true
 	}
-	if val.Kind() != reflect.Struct {
-		return nil
-	}
-	fieldVal := val.FieldByName(name)
-	if !fieldVal.IsValid() {
-		return nil
+	ok := true
+	check := func(v reflect.Value) {
+		validator, isValidator := v.Interface().(Validator)
+		if !isValidator {
+			return
+		}
+		for field, msgs := range validator.Validate() {
+			if len(key) > 0 {
+				field = key + "." + field
+			}
+			for _, msg := range msgs {
+				ok = false
+				errors.Add(field, msg)
+			}
+		}
 	}
-	return fieldVal.Interface()
-}
-
-func parseRequest(r *http.Request, v any) error {
-	contentType := r.Header.Get("Content-Type")
-	if contentType == "application/x-www-form-urlencoded" {
-		if err := r.ParseForm(); err != nil {
-			return fmt.Errorf("failed to parse form: %v", err)
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || seen[v.Pointer()] {
+			return true
 		}
-		val := reflect.ValueOf(v).Elem()
-		for i := 0; i < val.NumField(); i++ {
-			field := val.Type().Field(i)
-			formTag := field.Tag.Get("form")
-			formValue := r.FormValue(formTag)
-
-			if formValue == "" {
+		seen[v.Pointer()] = true
+		if v.CanInterface() {
+			check(v)
+		}
+		v = v.Elem()
+	case reflect.Interface:
+		if v.IsNil() {
+			return true
+		}
+		return runValidators(v.Elem(), key, errors, seen)
+	default:
+		if !v.CanInterface() {
+			return true
+		}
+		if v.CanAddr() {
+			check(v.Addr())
+		} else {
+			check(v)
+		}
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || !mayHoldStruct(field.Type) {
 				continue
 			}
+			path := errorFieldName(field.Name)
+			if len(key) > 0 {
+				path = key + "." + path
+			}
+			if !runValidators(v.Field(i), path, errors, seen) {
+				ok = false
+			}
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if !mayHoldStruct(v.Type().Elem()) {
+			break
+		}
+		for _, el := range elements(v, target{key: key}) {
+			if !runValidators(el.value, el.key, errors, seen) {
+				ok = false
+			}
+		}
+	}
+	return ok
+}
 
-			fieldVal := val.Field(i)
-			switch fieldVal.Kind() {
-			case reflect.Bool:
-				// There are cases where frontend libraries use "on" as the bool value
-				// think about toggles. Hence, let's try this first.
-				if formValue == "on" {
-					fieldVal.SetBool(true)
-				} else if formValue == "off" {
-					fieldVal.SetBool(false)
-					return nil
-				} else {
-					boolVal, err := strconv.ParseBool(formValue)
-					if err != nil {
-						return fmt.Errorf("failed to parse bool: %v", err)
-					}
-					fieldVal.SetBool(boolVal)
-				}
-			case reflect.String:
-				fieldVal.SetString(formValue)
-			case reflect.Int, reflect.Int32, reflect.Int64:
-				intVal, err := strconv.Atoi(formValue)
-				if err != nil {
-					return fmt.Errorf("failed to parse int: %v", err)
-				}
-				fieldVal.SetInt(int64(intVal))
-			case reflect.Uint, reflect.Uint32, reflect.Uint64:
-				intVal, err := strconv.Atoi(formValue)
-				if err != nil {
-					return fmt.Errorf("failed to parse int: %v", err)
-				}
-				fieldVal.SetUint(uint64(intVal))
-			case reflect.Float64:
-				floatVal, err := strconv.ParseFloat(formValue, 64)
-				if err != nil {
-					return fmt.Errorf("failed to parse float: %v", err)
+// mayHoldStruct reports whether values of the type can hold a struct,
+// which saves walking slices of strings and the like.
+func mayHoldStruct(typ reflect.Type) bool {
+	switch indirect(typ).Kind() {
+	case reflect.Struct, reflect.Interface, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+func validateTarget(ctx context.Context, t target, ruleSets []RuleSet, errors Errors) bool {
+	ok := true
+	value, present := fieldValue(t.value)
+	for _, set := range ruleSets {
+		if !present && !set.required {
+			continue
+		}
+		if set.each != nil {
+			for _, el := range elements(t.value, t) {
+				if !validateTarget(ctx, el.target, set.each, errors) {
+					ok = false
 				}
-				fieldVal.SetFloat(floatVal)
-			default:
-				return fmt.Errorf("unsupported kind %s", fieldVal.Kind())
 			}
+			continue
+		}
+		set.FieldValue = value
+		set.FieldName = t.field
+		set.FieldLabel = fieldLabel(t.parent, t.field)
+		set.parent = t.parent
+		valid := true
+		if set.ValidateContextFunc != nil {
+			var err error
+			if valid, err = set.ValidateContextFunc(ctx, set); err != nil {
+				ok = false
+				errors.Add("_error", err.Error())
+				continue
+			}
+		} else {
+			valid = set.ValidateFunc(set)
+		}
+		if !valid {
+			ok = false
+			errors.Add(t.key, message(ctx, set, t.key))
 		}
-
 	}
-	return nil
+	return ok
 }
 
 func isUppercase(s string) bool {
@@ -249,3 +249,164 @@ func TestMergeSchemas(t *testing.T) {
 	c := Merge(a, b)
 	assert.Equal(t, expected, c)
 }
+
+func TestValidateNestedPaths(t *testing.T) {
+	type Address struct {
+		City string
+	}
+	type Item struct {
+		Name     string
+		Quantity int
+	}
+	type Order struct {
+		Address  Address
+		Billing  *Address
+		Items    []Item
+		Prices   map[string]float64
+		Tags     []string
+		Comments map[string]string
+	}
+	order := Order{
+		Address: Address{City: ""},
+		Items: []Item{
+			{Name: "foo", Quantity: 1},
+			{Name: "bar", Quantity: 0},
+			{Name: "baz", Quantity: -1},
+		},
+		Prices:   map[string]float64{"eur": 10, "usd": -1},
+		Tags:     []string{"ok", "x"},
+		Comments: map[string]string{"a": "fine", "b": ""},
+	}
+	schema := Schema{
+		"address.city":     Rules(Required),
+		"billing.city":     Rules(Required),
+		"items[].quantity": Rules(GT(0)),
+		"items[0].name":    Rules(EQ("bar")),
+		"prices[usd]":      Rules(GTE(0.0)),
+		"tags":             Rules(Each(Min(2))),
+		"comments":         Rules(Each(Required)),
+	}
+	errors, ok := Validate(order, schema)
+	assert.False(t, ok)
+	assert.Len(t, errors, 7)
+	assert.True(t, errors.Has("address.city"))
+	// Nothing below a nil pointer is validated.
+	assert.False(t, errors.Has("billing.city"))
+	assert.False(t, errors.Has("items[0].quantity"))
+	assert.True(t, errors.Has("items[1].quantity"))
+	assert.True(t, errors.Has("items[2].quantity"))
+	assert.True(t, errors.Has("items[0].name"))
+	assert.True(t, errors.Has("prices[usd]"))
+	assert.True(t, errors.Has("tags[1]"))
+	assert.True(t, errors.Has("comments[b]"))
+}
+
+func TestValidateMalformedPath(t *testing.T) {
+	type Order struct {
+		Items []string
+	}
+	schema := Schema{
+		"items[0": Rules(Required),
+	}
+	assert.NotPanics(t, func() {
+		errors, ok := Validate(Order{}, schema)
+		assert.False(t, ok)
+		assert.Equal(t, []string{`invalid path "items[0"`}, errors["_error"])
+	})
+}
+
+func TestCrossFieldRules(t *testing.T) {
+	type Booking struct {
+		AccountType string
+		Company     string
+		Phone       string
+		Email       string
+		Password    string
+		Confirm     string
+		StartDate   time.Time
+		EndDate     time.Time
+	}
+	schema := Schema{
+		"confirm": Rules(EqualField("password")),
+		"company": Rules(RequiredIf("accountType", "business")),
+		"email":   Rules(RequiredWithout("phone")),
+		"phone":   Rules(RequiredWith("company")),
+		"endDate": Rules(AfterField("startDate")),
+	}
+	now := time.Now()
+	booking := Booking{
+		AccountType: "personal",
+		Email:       "foo@bar.com",
+		Password:    "hunter2",
+		Confirm:     "hunter2",
+		StartDate:   now,
+		EndDate:     now.Add(time.Hour),
+	}
+	errors, ok := Validate(booking, schema)
+	assert.True(t, ok)
+	assert.Empty(t, errors)
+
+	booking = Booking{
+		AccountType: "business",
+		Password:    "hunter2",
+		Confirm:     "hunter3",
+		StartDate:   now,
+		EndDate:     now.Add(-time.Hour),
+	}
+	errors, ok = Validate(booking, schema)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"should be equal to password"}, errors["confirm"])
+	assert.True(t, errors.Has("company"))
+	assert.True(t, errors.Has("email"))
+	assert.False(t, errors.Has("phone"))
+	assert.True(t, errors.Has("endDate"))
+
+	booking.Company = "ACME"
+	errors, _ = Validate(booking, schema)
+	assert.False(t, errors.Has("company"))
+	assert.True(t, errors.Has("phone"))
+}
+
+type lineItem struct {
+	Quantity int
+	Stock    int
+}
+
+func (item *lineItem) Validate() Errors {
+	errors := Errors{}
+	if item.Quantity > item.Stock {
+		errors.Add("quantity", "exceeds the stock")
+	}
+	return errors
+}
+
+type order struct {
+	Items []lineItem
+	Total int
+}
+
+func (o order) Validate() Errors {
+	errors := Errors{}
+	if o.Total < 0 {
+		errors.Add("total", "can't be negative")
+	}
+	return errors
+}
+
+func TestValidator(t *testing.T) {
+	o := order{
+		Items: []lineItem{{Quantity: 1, Stock: 2}, {Quantity: 3, Stock: 2}},
+		Total: -1,
+	}
+	errors, ok := Validate(&o, nil)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"can't be negative"}, errors["total"])
+	assert.Equal(t, []string{"exceeds the stock"}, errors["items[1].quantity"])
+	assert.False(t, errors.Has("items[0].quantity"))
+
+	o.Total = 1
+	o.Items[1].Stock = 3
+	errors, ok = Validate(&o, nil)
+	assert.True(t, ok)
+	assert.Empty(t, errors)
+}
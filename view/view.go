@@ -8,6 +8,7 @@ import (
 
 	"github.com/anthdm/superkit/kit"
 	"github.com/anthdm/superkit/kit/middleware"
+	"github.com/anthdm/superkit/kit/tenant"
 )
 
 // Asset is a view helper that returns the full asset path as a
@@ -51,3 +52,11 @@ func URL(ctx context.Context) *url.URL {
 func Request(ctx context.Context) *http.Request {
 	return getContextValue(ctx, middleware.RequestKey{}, &http.Request{})
 }
+
+// Tenant is a view helper that returns the current tenant.
+// If the request has no tenant, nil will be returned.
+//
+//	view.Tenant(ctx).Name
+func Tenant(ctx context.Context) *tenant.Tenant {
+	return tenant.FromContext(ctx)
+}